@@ -0,0 +1,157 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// RegistryABI is the ABI for the functions of the ENS registry used by the client
+const RegistryABI = `[
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"owner","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"ttl","outputs":[{"name":"","type":"uint64"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"recordExists","outputs":[{"name":"","type":"bool"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"name":"isApprovedForAll","outputs":[{"name":"","type":"bool"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"name":"setApprovalForAll","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setSubnodeOwner","outputs":[{"name":"","type":"bytes32"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"},{"name":"resolver","type":"address"},{"name":"ttl","type":"uint64"}],"name":"setSubnodeRecord","outputs":[],"type":"function"}
+]`
+
+// BaseRegistrarABI is the ABI for the functions of the .eth base registrar
+const BaseRegistrarABI = `[
+{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"nameExpires","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"name":"safeTransferFrom","outputs":[],"type":"function"}
+]`
+
+// ControllerABI is the ABI for the functions of the .eth registrar controller
+const ControllerABI = `[
+{"constant":true,"inputs":[{"name":"name","type":"string"}],"name":"available","outputs":[{"name":"","type":"bool"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"}],"name":"rentPrice","outputs":[{"components":[{"name":"base","type":"uint256"},{"name":"premium","type":"uint256"}],"name":"price","type":"tuple"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"minCommitmentAge","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+{"constant":true,"inputs":[],"name":"maxCommitmentAge","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"","type":"bytes32"}],"name":"commitments","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"},{"name":"resolver","type":"address"},{"name":"data","type":"bytes[]"},{"name":"reverseRecord","type":"bool"},{"name":"ownerControlledFuses","type":"uint16"}],"name":"makeCommitment","outputs":[{"name":"","type":"bytes32"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"commitment","type":"bytes32"}],"name":"commit","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"},{"name":"resolver","type":"address"},{"name":"data","type":"bytes[]"},{"name":"reverseRecord","type":"bool"},{"name":"ownerControlledFuses","type":"uint16"}],"name":"register","outputs":[],"payable":true,"type":"function"},
+{"constant":false,"inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"}],"name":"renew","outputs":[],"payable":true,"type":"function"},
+{"constant":true,"inputs":[],"name":"prices","outputs":[{"name":"","type":"address"}],"type":"function"}
+]`
+
+// ResolverABI is the ABI for the record functions of public resolvers.  The
+// Ether address setter of resolvers that predate multicoin addresses
+// overloads the multicoin setter so is bound as setAddr0
+const ResolverABI = `[
+{"constant":true,"inputs":[{"name":"interfaceID","type":"bytes4"}],"name":"supportsInterface","outputs":[{"name":"","type":"bool"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"contenthash","outputs":[{"name":"","type":"bytes"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"hash","type":"bytes"}],"name":"setContenthash","outputs":[],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"key","type":"string"}],"name":"text","outputs":[{"name":"","type":"string"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"key","type":"string"},{"name":"value","type":"string"}],"name":"setText","outputs":[],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"contentTypes","type":"uint256"}],"name":"ABI","outputs":[{"name":"","type":"uint256"},{"name":"","type":"bytes"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"contentType","type":"uint256"},{"name":"data","type":"bytes"}],"name":"setABI","outputs":[],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"pubkey","outputs":[{"name":"x","type":"bytes32"},{"name":"y","type":"bytes32"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"x","type":"bytes32"},{"name":"y","type":"bytes32"}],"name":"setPubkey","outputs":[],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"coinType","type":"uint256"}],"name":"addr","outputs":[{"name":"","type":"bytes"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"coinType","type":"uint256"},{"name":"a","type":"bytes"}],"name":"setAddr","outputs":[],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"},{"name":"interfaceID","type":"bytes4"}],"name":"interfaceImplementer","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"interfaceID","type":"bytes4"},{"name":"implementer","type":"address"}],"name":"setInterface","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"data","type":"bytes[]"}],"name":"multicall","outputs":[{"name":"results","type":"bytes[]"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"nodehash","type":"bytes32"},{"name":"data","type":"bytes[]"}],"name":"multicallWithNodeCheck","outputs":[{"name":"results","type":"bytes[]"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"addr","type":"address"}],"name":"setAddr","outputs":[],"type":"function"}
+]`
+
+// nameABI is the ABI for the name function of the resolvers of reverse records
+const nameABI = `[
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"}
+]`
+
+// addrABI is the ABI for the original Ether address functions of resolvers,
+// which are overloaded by the multicoin address functions in ResolverABI
+const addrABI = `[
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"addr","type":"address"}],"name":"setAddr","outputs":[],"type":"function"}
+]`
+
+// NameWrapperABI is the ABI for the functions of the NameWrapper
+const NameWrapperABI = `[
+{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"id","type":"uint256"}],"name":"getData","outputs":[{"name":"owner","type":"address"},{"name":"fuses","type":"uint32"},{"name":"expiry","type":"uint64"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"names","outputs":[{"name":"","type":"bytes"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"name","type":"bytes"},{"name":"wrappedOwner","type":"address"},{"name":"resolver","type":"address"}],"name":"wrap","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"labelhash","type":"bytes32"},{"name":"registrant","type":"address"},{"name":"controller","type":"address"}],"name":"unwrapETH2LD","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"parentNode","type":"bytes32"},{"name":"labelhash","type":"bytes32"},{"name":"controller","type":"address"}],"name":"unwrap","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"ownerControlledFuses","type":"uint16"}],"name":"setFuses","outputs":[{"name":"","type":"uint32"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"resolver","type":"address"}],"name":"setResolver","outputs":[],"type":"function"},
+{"constant":false,"inputs":[{"name":"parentNode","type":"bytes32"},{"name":"label","type":"string"},{"name":"owner","type":"address"},{"name":"fuses","type":"uint32"},{"name":"expiry","type":"uint64"}],"name":"setSubnodeOwner","outputs":[{"name":"","type":"bytes32"}],"type":"function"},
+{"constant":false,"inputs":[{"name":"parentNode","type":"bytes32"},{"name":"label","type":"string"},{"name":"owner","type":"address"},{"name":"resolver","type":"address"},{"name":"ttl","type":"uint64"},{"name":"fuses","type":"uint32"},{"name":"expiry","type":"uint64"}],"name":"setSubnodeRecord","outputs":[{"name":"","type":"bytes32"}],"type":"function"}
+]`
+
+// safeTransferWithDataJSON is the ABI for the ERC-721 transfer with data
+// used to wrap names directly under .eth, which is overloaded by the
+// transfer without data in BaseRegistrarABI
+const safeTransferWithDataJSON = `[
+{"constant":false,"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"type":"function"}
+]`
+
+// priceOracleJSON is the ABI for the USD oracle lookup of the price oracle
+// of the registrar controller
+const priceOracleJSON = `[
+{"constant":true,"inputs":[],"name":"usdOracle","outputs":[{"name":"","type":"address"}],"type":"function"}
+]`
+
+// usdOracleJSON is the ABI of the Chainlink aggregator used by the price oracle
+const usdOracleJSON = `[
+{"constant":true,"inputs":[],"name":"latestAnswer","outputs":[{"name":"","type":"int256"}],"type":"function"}
+]`
+
+// bulkRenewalJSON is the ABI for the function of the bulk renewal contract
+// that renews several names directly under .eth in one transaction
+const bulkRenewalJSON = `[
+{"constant":false,"inputs":[{"name":"names","type":"string[]"},{"name":"duration","type":"uint256"}],"name":"renewAll","outputs":[],"payable":true,"type":"function"}
+]`
+
+// dnsRegistrarJSON is the ABI for the DNS registrar that owns TLDs enabled
+// for DNS names
+const dnsRegistrarJSON = `[
+{"constant":false,"inputs":[{"name":"name","type":"bytes"},{"components":[{"name":"rrset","type":"bytes"},{"name":"sig","type":"bytes"}],"name":"input","type":"tuple[]"}],"name":"proveAndClaim","outputs":[],"type":"function"}
+]`
+
+// The ABIs of the client, parsed once when the package is loaded
+var (
+	registryABI             = mustParseABI(RegistryABI)
+	baseRegistrarABI        = mustParseABI(BaseRegistrarABI)
+	controllerABI           = mustParseABI(ControllerABI)
+	resolverABI             = mustParseABI(ResolverABI)
+	nameResolverABI         = mustParseABI(nameABI)
+	addrResolverABI         = mustParseABI(addrABI)
+	nameWrapperABI          = mustParseABI(NameWrapperABI)
+	safeTransferWithDataABI = mustParseABI(safeTransferWithDataJSON)
+	priceOracleABI          = mustParseABI(priceOracleJSON)
+	usdOracleABI            = mustParseABI(usdOracleJSON)
+	bulkRenewalABI          = mustParseABI(bulkRenewalJSON)
+	dnsRegistrarABI         = mustParseABI(dnsRegistrarJSON)
+)
+
+// mustParseABI parses an ABI, panicking if it is invalid as the ABIs of the
+// client are fixed
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,229 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/orinocopay/go-etherutils/ens/registrarcontract"
+)
+
+// AuctionEntry is the entry for a name in the auction registrar
+type AuctionEntry struct {
+	// State is the state of the name, such as Available, Bidding,
+	// Revealing, Won or Owned
+	State string
+	// Deed is the address of the deed holding the winning bid
+	Deed common.Address
+	// RegistrationDate is the time at which the auction ends
+	RegistrationDate time.Time
+	// Value is the amount held by the deed
+	Value *big.Int
+	// HighestBid is the highest revealed bid
+	HighestBid *big.Int
+}
+
+// auctionRegistrar binds the auction registrar, which is the owner of eth in
+// the legacy registry if the client has one, or otherwise in the registry
+func (c *Client) auctionRegistrar(ctx context.Context) (*registrarcontract.RegistrarContract, error) {
+	registry := c.registry
+	if c.legacy != (common.Address{}) {
+		registry = c.legacy
+	}
+	var address common.Address
+	if err := c.call(ctx, c.contract(registry, registryABI), &address, "owner", NameHash("eth")); err != nil {
+		return nil, err
+	}
+	return registrarcontract.NewRegistrarContract(address, c.cache)
+}
+
+// auctionSession creates a session with the auction registrar for a signer
+func (c *Client) auctionSession(ctx context.Context, signer *bind.TransactOpts) (*registrarcontract.RegistrarContractSession, error) {
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	registrar, err := c.auctionRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session := &registrarcontract.RegistrarContractSession{
+		Contract:     registrar,
+		CallOpts:     *c.callOpts(ctx),
+		TransactOpts: *signer,
+	}
+	session.TransactOpts.Context = ctx
+	return session, nil
+}
+
+// AuctionEntry obtains the entry for a name directly under .eth in the
+// auction registrar
+func (c *Client) AuctionEntry(ctx context.Context, name string) (*AuctionEntry, error) {
+	registrar, err := c.auctionRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, deed, registrationDate, value, highestBid, err := ens.Entry(registrar, c.backend, name)
+	if err != nil {
+		return nil, err
+	}
+	return &AuctionEntry{
+		State:            state,
+		Deed:             deed,
+		RegistrationDate: registrationDate,
+		Value:            value,
+		HighestBid:       highestBid,
+	}, nil
+}
+
+// AuctionState obtains the state of a name directly under .eth in the
+// auction registrar, such as Available, Bidding, Revealing, Won or Owned
+func (c *Client) AuctionState(ctx context.Context, name string) (string, error) {
+	registrar, err := c.auctionRegistrar(ctx)
+	if err != nil {
+		return "", err
+	}
+	return ens.State(registrar, c.backend, name)
+}
+
+// StartAuction creates a transaction starting the auction for a name
+func (c *Client) StartAuction(ctx context.Context, signer *bind.TransactOpts, name string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.StartAuction(session, name)
+}
+
+// StartAuctionAndBid creates a transaction starting the auction for a name
+// along with auctions for a number of dummy names to hide it, and placing a
+// sealed bid on it.  The value of the signer is the deposit for the bid,
+// which must be at least the bid
+func (c *Client) StartAuctionAndBid(ctx context.Context, signer *bind.TransactOpts, name string, bidder common.Address, bid *big.Int, salt string, dummies int) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.StartAuctionAndBid(session, name, &bidder, *bid, salt, dummies)
+}
+
+// Bid creates a transaction placing a sealed bid on a name.  The value of
+// the signer is the deposit for the bid, which must be at least the bid
+func (c *Client) Bid(ctx context.Context, signer *bind.TransactOpts, name string, bidder common.Address, bid *big.Int, salt string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.NewBid(session, name, &bidder, *bid, salt)
+}
+
+// RevealBid creates a transaction revealing a sealed bid on a name
+func (c *Client) RevealBid(ctx context.Context, signer *bind.TransactOpts, name string, bidder common.Address, bid *big.Int, salt string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.RevealBid(session, name, &bidder, *bid, salt)
+}
+
+// SealedBid obtains the address of the deed holding a sealed bid on a name;
+// it is zero if there is no matching bid
+func (c *Client) SealedBid(ctx context.Context, name string, bidder common.Address, bid *big.Int, salt string) (common.Address, error) {
+	registrar, err := c.auctionRegistrar(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	seal, err := c.sealedBidHash(ctx, registrar, name, bidder, bid, salt)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return registrar.SealedBids(c.callOpts(ctx), bidder, seal)
+}
+
+// CancelBid creates a transaction cancelling a sealed bid that was not
+// revealed in time, returning part of its deposit to the bidder
+func (c *Client) CancelBid(ctx context.Context, signer *bind.TransactOpts, name string, bidder common.Address, bid *big.Int, salt string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	seal, err := c.sealedBidHash(ctx, session.Contract, name, bidder, bid, salt)
+	if err != nil {
+		return nil, err
+	}
+	return session.Contract.CancelBid(&session.TransactOpts, bidder, seal)
+}
+
+// sealedBidHash obtains the hash that seals a bid
+func (c *Client) sealedBidHash(ctx context.Context, registrar *registrarcontract.RegistrarContract, name string, bidder common.Address, bid *big.Int, salt string) (seal [32]byte, err error) {
+	label, err := ens.Domain(name)
+	if err != nil {
+		return
+	}
+	var saltHash [32]byte
+	copy(saltHash[:], crypto.Keccak256([]byte(salt)))
+	return registrar.ShaBid(c.callOpts(ctx), LabelHash(label), bidder, bid, saltHash)
+}
+
+// FinishAuction creates a transaction finishing the auction for a name,
+// which registers it to the winning bidder
+func (c *Client) FinishAuction(ctx context.Context, signer *bind.TransactOpts, name string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.FinishAuction(session, name)
+}
+
+// ReleaseDeed creates a transaction releasing the deed of a name won at
+// auction, returning its deposit to the owner of the deed and giving up
+// the name
+func (c *Client) ReleaseDeed(ctx context.Context, signer *bind.TransactOpts, name string) (*types.Transaction, error) {
+	label, err := ens.Domain(name)
+	if err != nil {
+		return nil, err
+	}
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return session.Contract.ReleaseDeed(&session.TransactOpts, LabelHash(label))
+}
+
+// InvalidateName creates a transaction invalidating a name that is shorter
+// than the auction registrar allows
+func (c *Client) InvalidateName(ctx context.Context, signer *bind.TransactOpts, name string) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.InvalidateName(session, name)
+}
+
+// TransferDeed creates a transaction transferring the deed of a name won at
+// auction to a new owner
+func (c *Client) TransferDeed(ctx context.Context, signer *bind.TransactOpts, name string, owner common.Address) (*types.Transaction, error) {
+	session, err := c.auctionSession(ctx, signer)
+	if err != nil {
+		return nil, err
+	}
+	return ens.Transfer(session, name, owner)
+}
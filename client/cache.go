@@ -0,0 +1,95 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// cachedSelectors are the selectors of the registry lookups that are cached
+var cachedSelectors = [][]byte{
+	crypto.Keccak256([]byte("owner(bytes32)"))[:4],
+	crypto.Keccak256([]byte("resolver(bytes32)"))[:4],
+}
+
+// cachingBackend is a contract backend that caches the owners and resolvers
+// read from the registry, keyed by the node looked up, so that repeated
+// lookups of the same name do not go back to the node.  Calls at a specific
+// block and all other calls are passed straight through
+type cachingBackend struct {
+	bind.ContractBackend
+	registry common.Address
+
+	mu      sync.Mutex
+	results map[string][]byte
+}
+
+// newCachingBackend creates a caching backend for a registry
+func newCachingBackend(backend bind.ContractBackend, registry common.Address) *cachingBackend {
+	return &cachingBackend{
+		ContractBackend: backend,
+		registry:        registry,
+		results:         make(map[string][]byte),
+	}
+}
+
+// CallContract calls a contract, using the cached result for registry lookups
+func (b *cachingBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil || !b.cacheable(call) {
+		return b.ContractBackend.CallContract(ctx, call, blockNumber)
+	}
+	key := string(call.Data)
+	b.mu.Lock()
+	result, exists := b.results[key]
+	b.mu.Unlock()
+	if exists {
+		return common.CopyBytes(result), nil
+	}
+	result, err := b.ContractBackend.CallContract(ctx, call, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.results[key] = common.CopyBytes(result)
+	b.mu.Unlock()
+	return result, nil
+}
+
+// cacheable returns true if a call is a registry lookup that can be cached
+func (b *cachingBackend) cacheable(call ethereum.CallMsg) bool {
+	if call.To == nil || *call.To != b.registry || len(call.Data) != 36 || call.Value != nil && call.Value.Sign() != 0 {
+		return false
+	}
+	for _, selector := range cachedSelectors {
+		if bytes.Equal(call.Data[:4], selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// clear empties the cache
+func (b *cachingBackend) clear() {
+	b.mu.Lock()
+	b.results = make(map[string][]byte)
+	b.mu.Unlock()
+}
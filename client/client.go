@@ -0,0 +1,186 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client provides the core operations of the Ethereum Name Service
+// (ENS) for use by Go programs.  Names passed to the client must already be
+// normalized.
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/orinocopay/go-etherutils/ens"
+)
+
+// DefaultRegistry is the address of the ENS registry on mainnet and the
+// public testnets
+var DefaultRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// Config is the configuration of a client
+type Config struct {
+	// Registry is the address of the ENS registry; defaults to DefaultRegistry
+	Registry common.Address
+	// BaseRegistrar is the address of the .eth base registrar; defaults to
+	// the owner of eth in the registry
+	BaseRegistrar common.Address
+	// Controller is the address of the .eth registrar controller; required
+	// to check availability, obtain prices and renew names
+	Controller common.Address
+	// NameWrapper is the address of the NameWrapper; required to work with
+	// wrapped names
+	NameWrapper common.Address
+	// BulkRenewal is the address of the contract that renews several names
+	// directly under .eth at once, if any
+	BulkRenewal common.Address
+	// LegacyRegistry is the address of the registry in use before the 2020
+	// migration, if any; the auction registrar is the owner of eth in it,
+	// or otherwise in Registry
+	LegacyRegistry common.Address
+	// BlockNumber is the block at which state is read; defaults to the
+	// latest block
+	BlockNumber *big.Int
+	// CCIPTimeout is the timeout for requests to offchain (EIP-3668)
+	// gateways; defaults to 10 seconds
+	CCIPTimeout time.Duration
+	// CCIPAllow is the list of hosts of the offchain gateways that may be
+	// used; defaults to all
+	CCIPAllow []string
+}
+
+// Client carries out ENS operations against a node.  Operations that send
+// transactions take a signer in the form of transaction options, and return
+// the transaction created with those options.  Owners and resolvers read
+// from the registry are cached for the life of the client; see ClearCache
+type Client struct {
+	backend       *ethclient.Client
+	cache         *cachingBackend
+	chainID       *big.Int
+	registry      common.Address
+	baseRegistrar common.Address
+	controller    common.Address
+	nameWrapper   common.Address
+	bulkRenewal   common.Address
+	legacy        common.Address
+	blockNumber   *big.Int
+	ccipTimeout   time.Duration
+	ccipAllow     []string
+}
+
+// New creates a client for the chain with the given ID
+func New(backend *ethclient.Client, chainID *big.Int, config *Config) (*Client, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if chainID == nil {
+		return nil, errors.New("no chain ID supplied")
+	}
+	c := &Client{
+		backend: backend,
+		chainID: chainID,
+	}
+	if config != nil {
+		c.registry = config.Registry
+		c.baseRegistrar = config.BaseRegistrar
+		c.controller = config.Controller
+		c.nameWrapper = config.NameWrapper
+		c.bulkRenewal = config.BulkRenewal
+		c.legacy = config.LegacyRegistry
+		c.blockNumber = config.BlockNumber
+		c.ccipTimeout = config.CCIPTimeout
+		c.ccipAllow = config.CCIPAllow
+	}
+	if c.registry == (common.Address{}) {
+		c.registry = DefaultRegistry
+	}
+	if c.ccipTimeout == 0 {
+		c.ccipTimeout = 10 * time.Second
+	}
+	c.cache = newCachingBackend(backend, c.registry)
+	return c, nil
+}
+
+// ChainID returns the ID of the chain of the client
+func (c *Client) ChainID() *big.Int {
+	return new(big.Int).Set(c.chainID)
+}
+
+// Registry returns the address of the registry of the client
+func (c *Client) Registry() common.Address {
+	return c.registry
+}
+
+// Backend returns the backend of the client.  Owners and resolvers read
+// from the registry through it are cached until ClearCache is called, so
+// bindings created with it share the cache of the client
+func (c *Client) Backend() bind.ContractBackend {
+	return c.cache
+}
+
+// ClearCache clears the cached owners and resolvers, which should be done
+// once a transaction that changes the registry has been mined
+func (c *Client) ClearCache() {
+	c.cache.clear()
+}
+
+// NameHash returns the hash of a name
+func NameHash(name string) [32]byte {
+	return ens.NameHash(name)
+}
+
+// LabelHash returns the hash of a single label
+func LabelHash(label string) [32]byte {
+	return ens.LabelHash(label)
+}
+
+// contract binds a contract at an address
+func (c *Client) contract(address common.Address, parsed abi.ABI) *bind.BoundContract {
+	return bind.NewBoundContract(address, parsed, c.cache, &RevertReasonTransactor{c.backend}, c.backend)
+}
+
+// callOpts returns the options for calls made by the client
+func (c *Client) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: c.blockNumber}
+}
+
+// call calls a constant method of a contract, placing the first return
+// value in result
+func (c *Client) call(ctx context.Context, contract *bind.BoundContract, result interface{}, method string, params ...interface{}) error {
+	var out []interface{}
+	if err := contract.Call(c.callOpts(ctx), &out, method, params...); err != nil {
+		return err
+	}
+	if len(out) == 0 {
+		return errors.New("no value returned")
+	}
+	reflect.ValueOf(result).Elem().Set(reflect.ValueOf(abi.ConvertType(out[0], result)).Elem())
+	return nil
+}
+
+// transact creates a transaction calling a method of a contract, signed by signer
+func transact(ctx context.Context, contract *bind.BoundContract, signer *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	opts := *signer
+	opts.Context = ctx
+	return contract.Transact(&opts, method, params...)
+}
@@ -0,0 +1,66 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DNSEncode encodes a name in DNS wire format, as a sequence of labels that
+// are each prefixed by their length and terminated by a zero length
+func DNSEncode(name string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid label length in %s", name)
+		}
+		buf.WriteByte(byte(len(label)))
+		buf.WriteString(label)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes(), nil
+}
+
+// DNSDecode decodes a name in DNS wire format
+func DNSDecode(data []byte) (string, error) {
+	labels := make([]string, 0)
+	for offset := 0; ; {
+		if offset >= len(data) {
+			return "", errors.New("name is not terminated")
+		}
+		length := int(data[offset])
+		offset++
+		if length == 0 {
+			if offset != len(data) {
+				return "", errors.New("data follows the end of the name")
+			}
+			break
+		}
+		if length > 63 {
+			return "", fmt.Errorf("invalid label length %d", length)
+		}
+		if offset+length > len(data) {
+			return "", errors.New("label is truncated")
+		}
+		labels = append(labels, string(data[offset:offset+length]))
+		offset += length
+	}
+	if len(labels) == 0 {
+		return "", errors.New("name has no labels")
+	}
+	return strings.Join(labels, "."), nil
+}
@@ -0,0 +1,56 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoDNSRegistrar is returned when importing a DNS name whose TLD is not
+// enabled for DNS names
+var ErrNoDNSRegistrar = errors.New("TLD is not enabled for DNS names")
+
+// RRSetWithSignature is a signed set of DNS records in the form accepted by
+// the ENS DNSSEC oracle.  Rrset is the RRSIG record data without the
+// signature, followed by the records in canonical form
+type RRSetWithSignature struct {
+	Rrset []byte
+	Sig   []byte
+}
+
+// ImportDNSName creates a transaction claiming a DNS name in ENS for the
+// owner given in its _ens TXT record, proven by the chain of signed record
+// sets from the DNS root down to that record.  The transaction is sent to
+// the DNS registrar that owns the TLD of the name in the registry
+func (c *Client) ImportDNSName(ctx context.Context, signer *bind.TransactOpts, name string, proof []RRSetWithSignature) (*types.Transaction, error) {
+	name = strings.TrimSuffix(name, ".")
+	dnsName, err := DNSEncode(name)
+	if err != nil {
+		return nil, err
+	}
+	registrar, err := c.Owner(ctx, name[strings.LastIndex(name, ".")+1:])
+	if err != nil {
+		return nil, err
+	}
+	if registrar == (common.Address{}) {
+		return nil, ErrNoDNSRegistrar
+	}
+	return transact(ctx, c.contract(registrar, dnsRegistrarABI), signer, "proveAndClaim", dnsName, proof)
+}
@@ -0,0 +1,139 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fuses of wrapped names.  Fuses can only be burned, not restored, until the
+// name expires
+const (
+	// CannotUnwrap stops the name being unwrapped
+	CannotUnwrap uint32 = 1 << iota
+	// CannotBurnFuses stops further fuses being burned
+	CannotBurnFuses
+	// CannotTransfer stops the name being transferred
+	CannotTransfer
+	// CannotSetResolver stops the resolver being changed
+	CannotSetResolver
+	// CannotSetTTL stops the TTL being changed
+	CannotSetTTL
+	// CannotCreateSubdomain stops new subdomains being created
+	CannotCreateSubdomain
+	// CannotApprove stops an approved address being set for the name
+	CannotApprove
+)
+
+// Fuses that can only be burned by the owner of the parent
+const (
+	// ParentCannotControl stops the owner of the parent changing the name
+	ParentCannotControl uint32 = 1 << (16 + iota)
+	// IsDotEth is set for names directly under .eth
+	IsDotEth
+	// CanExtendExpiry allows the owner of the name to extend its expiry
+	CanExtendExpiry
+)
+
+// ownerControlledFuses are the fuses that can be burned by the owner of a name
+const ownerControlledFuses uint32 = 0xffff
+
+// fuseNames are the names of the fuses, in order of their bits
+var fuseNames = []struct {
+	fuse uint32
+	name string
+}{
+	{CannotUnwrap, "CANNOT_UNWRAP"},
+	{CannotBurnFuses, "CANNOT_BURN_FUSES"},
+	{CannotTransfer, "CANNOT_TRANSFER"},
+	{CannotSetResolver, "CANNOT_SET_RESOLVER"},
+	{CannotSetTTL, "CANNOT_SET_TTL"},
+	{CannotCreateSubdomain, "CANNOT_CREATE_SUBDOMAIN"},
+	{CannotApprove, "CANNOT_APPROVE"},
+	{ParentCannotControl, "PARENT_CANNOT_CONTROL"},
+	{IsDotEth, "IS_DOT_ETH"},
+	{CanExtendExpiry, "CAN_EXTEND_EXPIRY"},
+}
+
+// FuseNames returns the names of the fuses that are burned
+func FuseNames(fuses uint32) []string {
+	names := make([]string, 0)
+	for _, fuseName := range fuseNames {
+		if fuses&fuseName.fuse != 0 {
+			names = append(names, fuseName.name)
+		}
+	}
+	return names
+}
+
+// ParseFuses returns the bitfield of fuses given by name, for example
+// CANNOT_UNWRAP.  Names are case-insensitive
+func ParseFuses(names []string) (uint32, error) {
+	var fuses uint32
+	for _, name := range names {
+		found := false
+		for _, fuseName := range fuseNames {
+			if strings.EqualFold(strings.TrimSpace(name), fuseName.name) {
+				fuses |= fuseName.fuse
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown fuse %q", name)
+		}
+	}
+	return fuses, nil
+}
+
+// CheckFuseBurn returns an error if the NameWrapper would refuse to burn
+// fuses of a name that already has the current fuses burned
+func CheckFuseBurn(current uint32, fuses uint32) error {
+	if fuses&^ownerControlledFuses != 0 {
+		return fmt.Errorf("%s can only be burned by the owner of the parent", strings.Join(FuseNames(fuses&^ownerControlledFuses), ", "))
+	}
+	if current&CannotBurnFuses != 0 {
+		return fmt.Errorf("CANNOT_BURN_FUSES is burned")
+	}
+	combined := current | fuses
+	if combined&ownerControlledFuses&^CannotUnwrap != 0 && combined&CannotUnwrap == 0 {
+		return fmt.Errorf("CANNOT_UNWRAP must be burned before any other fuse")
+	}
+	if combined&CannotUnwrap != 0 && current&ParentCannotControl == 0 {
+		return fmt.Errorf("PARENT_CANNOT_CONTROL must be burned by the owner of the parent before CANNOT_UNWRAP")
+	}
+	return nil
+}
+
+// CheckSubnameFuses returns an error if the NameWrapper would refuse to
+// create a subname with fuses under a parent that has the parent fuses burned
+func CheckSubnameFuses(parentFuses uint32, fuses uint32) error {
+	if fuses&IsDotEth != 0 {
+		return fmt.Errorf("IS_DOT_ETH cannot be burned")
+	}
+	if parentFuses&CannotCreateSubdomain != 0 {
+		return fmt.Errorf("CANNOT_CREATE_SUBDOMAIN is burned on the parent")
+	}
+	if fuses&ParentCannotControl != 0 && parentFuses&CannotUnwrap == 0 {
+		return fmt.Errorf("CANNOT_UNWRAP must be burned on the parent before PARENT_CANNOT_CONTROL")
+	}
+	if fuses&ownerControlledFuses&^CannotUnwrap != 0 && fuses&CannotUnwrap == 0 {
+		return fmt.Errorf("CANNOT_UNWRAP must be burned before any other fuse")
+	}
+	if fuses&CannotUnwrap != 0 && fuses&ParentCannotControl == 0 {
+		return fmt.Errorf("PARENT_CANNOT_CONTROL must be burned before CANNOT_UNWRAP")
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoNameWrapper is returned by operations on wrapped names when the
+// client has no NameWrapper
+var ErrNoNameWrapper = errors.New("no NameWrapper configured")
+
+// ErrNotWrapped is returned by operations on wrapped names when the name
+// is not wrapped
+var ErrNotWrapped = errors.New("name is not wrapped")
+
+// WrappedName is the data held by the NameWrapper for a wrapped name
+type WrappedName struct {
+	// Owner is the owner of the wrapped name
+	Owner common.Address
+	// Fuses is the bitfield of the burned fuses
+	Fuses uint32
+	// Expiry is the time at which the fuses of the name expire
+	Expiry time.Time
+}
+
+// NameWrapper returns the address of the NameWrapper of the client
+func (c *Client) NameWrapper() common.Address {
+	return c.nameWrapper
+}
+
+// nameWrapperContract binds the NameWrapper
+func (c *Client) nameWrapperContract() (*bind.BoundContract, error) {
+	if c.nameWrapper == (common.Address{}) {
+		return nil, ErrNoNameWrapper
+	}
+	return c.contract(c.nameWrapper, nameWrapperABI), nil
+}
+
+// IsWrapped returns true if a name is owned by the NameWrapper in the registry
+func (c *Client) IsWrapped(ctx context.Context, name string) (bool, error) {
+	if c.nameWrapper == (common.Address{}) {
+		return false, nil
+	}
+	owner, err := c.Owner(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return owner == c.nameWrapper, nil
+}
+
+// WrappedData obtains the owner, fuses and expiry of a wrapped name
+func (c *Client) WrappedData(ctx context.Context, name string) (*WrappedName, error) {
+	wrapped, err := c.IsWrapped(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !wrapped {
+		return nil, ErrNotWrapped
+	}
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	node := NameHash(name)
+	var out []interface{}
+	if err = nameWrapper.Call(c.callOpts(ctx), &out, "getData", new(big.Int).SetBytes(node[:])); err != nil {
+		return nil, err
+	}
+	if len(out) != 3 {
+		return nil, errors.New("unexpected data returned")
+	}
+	return &WrappedName{
+		Owner:  *abi.ConvertType(out[0], new(common.Address)).(*common.Address),
+		Fuses:  *abi.ConvertType(out[1], new(uint32)).(*uint32),
+		Expiry: time.Unix(int64(*abi.ConvertType(out[2], new(uint64)).(*uint64)), 0),
+	}, nil
+}
+
+// WrappedOwner obtains the owner of a wrapped name
+func (c *Client) WrappedOwner(ctx context.Context, name string) (common.Address, error) {
+	data, err := c.WrappedData(ctx, name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return data.Owner, nil
+}
+
+// Fuses obtains the bitfield of the burned fuses of a wrapped name
+func (c *Client) Fuses(ctx context.Context, name string) (uint32, error) {
+	data, err := c.WrappedData(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return data.Fuses, nil
+}
+
+// WrappedTokenOwner obtains the owner of a wrapped name from its token ID,
+// which is its name hash; it is zero if the token does not exist or has
+// expired
+func (c *Client) WrappedTokenOwner(ctx context.Context, id *big.Int) (owner common.Address, err error) {
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, nameWrapper, &owner, "ownerOf", id)
+	return
+}
+
+// WrappedTokenName obtains the name of a wrapped name from its token ID,
+// which the NameWrapper records when the name is wrapped
+func (c *Client) WrappedTokenName(ctx context.Context, id *big.Int) (string, error) {
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return "", err
+	}
+	var dnsName []byte
+	if err = c.call(ctx, nameWrapper, &dnsName, "names", common.BigToHash(id)); err != nil {
+		return "", err
+	}
+	return DNSDecode(dnsName)
+}
+
+// NameWrapperApproved returns true if the NameWrapper can manage the names
+// that an address owns in the registry
+func (c *Client) NameWrapperApproved(ctx context.Context, owner common.Address) (approved bool, err error) {
+	if c.nameWrapper == (common.Address{}) {
+		return false, ErrNoNameWrapper
+	}
+	registry := c.contract(c.registry, registryABI)
+	err = c.call(ctx, registry, &approved, "isApprovedForAll", owner, c.nameWrapper)
+	return
+}
+
+// ApproveNameWrapper creates a transaction allowing the NameWrapper to manage
+// the names that the signer owns in the registry, which is required before
+// wrapping names that are not directly under .eth
+func (c *Client) ApproveNameWrapper(ctx context.Context, signer *bind.TransactOpts) (*types.Transaction, error) {
+	if c.nameWrapper == (common.Address{}) {
+		return nil, ErrNoNameWrapper
+	}
+	registry := c.contract(c.registry, registryABI)
+	return transact(ctx, registry, signer, "setApprovalForAll", c.nameWrapper, true)
+}
+
+// Wrap creates a transaction wrapping a name, giving the wrapped name to
+// owner and setting its resolver.  A name directly under .eth is wrapped by
+// its registrant; any other name is wrapped by its owner in the registry,
+// who must first have approved the NameWrapper
+func (c *Client) Wrap(ctx context.Context, signer *bind.TransactOpts, name string, owner common.Address, resolver common.Address) (*types.Transaction, error) {
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	if c.nameWrapper == (common.Address{}) {
+		return nil, ErrNoNameWrapper
+	}
+	if id, err := LabelID(name); err == nil {
+		// Names directly under .eth are wrapped by transferring the token
+		data, err := wrapETH2LDData(strings.TrimSuffix(name, ".eth"), owner, resolver)
+		if err != nil {
+			return nil, err
+		}
+		baseRegistrar, err := c.baseRegistrarAddress(ctx)
+		if err != nil {
+			return nil, err
+		}
+		token := c.contract(baseRegistrar, safeTransferWithDataABI)
+		return transact(ctx, token, signer, "safeTransferFrom", signer.From, c.nameWrapper, id, data)
+	}
+	encoded, err := DNSEncode(name)
+	if err != nil {
+		return nil, err
+	}
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, nameWrapper, signer, "wrap", encoded, owner, resolver)
+}
+
+// wrapETH2LDData encodes the data passed with the transfer of a name
+// directly under .eth to the NameWrapper
+func wrapETH2LDData(label string, owner common.Address, resolver common.Address) ([]byte, error) {
+	stringType, _ := abi.NewType("string", "", nil)
+	addressType, _ := abi.NewType("address", "", nil)
+	uint16Type, _ := abi.NewType("uint16", "", nil)
+	args := abi.Arguments{{Type: stringType}, {Type: addressType}, {Type: uint16Type}, {Type: addressType}}
+	return args.Pack(label, owner, uint16(0), resolver)
+}
+
+// Unwrap creates a transaction unwrapping a name, giving it to owner in the
+// registry and, for a name directly under .eth, as registrant.  The signer
+// must be the owner of the wrapped name
+func (c *Client) Unwrap(ctx context.Context, signer *bind.TransactOpts, name string, owner common.Address) (*types.Transaction, error) {
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("top-level names cannot be unwrapped")
+	}
+	if parts[1] == "eth" {
+		return transact(ctx, nameWrapper, signer, "unwrapETH2LD", LabelHash(parts[0]), owner, owner)
+	}
+	return transact(ctx, nameWrapper, signer, "unwrap", NameHash(parts[1]), LabelHash(parts[0]), owner)
+}
+
+// BurnFuses creates a transaction burning owner-controlled fuses of a wrapped
+// name.  The signer must be the owner of the wrapped name.  Burned fuses
+// cannot be restored until the name expires
+func (c *Client) BurnFuses(ctx context.Context, signer *bind.TransactOpts, name string, fuses uint32) (*types.Transaction, error) {
+	if fuses&^ownerControlledFuses != 0 {
+		return nil, errors.New("only owner-controlled fuses can be burned")
+	}
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, nameWrapper, signer, "setFuses", NameHash(name), uint16(fuses))
+}
+
+// SetWrappedResolver creates a transaction setting the resolver of a wrapped
+// name, which the registry only accepts from the NameWrapper.  The signer
+// must be the owner of the wrapped name, and CANNOT_SET_RESOLVER must not
+// be burned
+func (c *Client) SetWrappedResolver(ctx context.Context, signer *bind.TransactOpts, name string, resolver common.Address) (*types.Transaction, error) {
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, nameWrapper, signer, "setResolver", NameHash(name), resolver)
+}
+
+// CreateWrappedSubname creates a transaction creating a wrapped subname of a
+// wrapped parent with burned fuses and an expiry, which the NameWrapper
+// limits to that of the parent.  The resolver is only set if it is not
+// zero.  The signer must be the owner of the wrapped parent
+func (c *Client) CreateWrappedSubname(ctx context.Context, signer *bind.TransactOpts, parent string, label string, owner common.Address, resolver common.Address, fuses uint32, expiry time.Time) (*types.Transaction, error) {
+	nameWrapper, err := c.nameWrapperContract()
+	if err != nil {
+		return nil, err
+	}
+	var expirySeconds uint64
+	if !expiry.IsZero() {
+		expirySeconds = uint64(expiry.Unix())
+	}
+	if resolver == (common.Address{}) {
+		return transact(ctx, nameWrapper, signer, "setSubnodeOwner", NameHash(parent), label, owner, fuses, expirySeconds)
+	}
+	return transact(ctx, nameWrapper, signer, "setSubnodeRecord", NameHash(parent), label, owner, resolver, uint64(0), fuses, expirySeconds)
+}
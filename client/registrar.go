@@ -0,0 +1,266 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoController is returned by registration operations when the client
+// has no registrar controller
+var ErrNoController = errors.New("no registrar controller configured")
+
+// ErrNoBulkRenewal is returned by bulk renewals when the client has no bulk
+// renewal contract
+var ErrNoBulkRenewal = errors.New("no bulk renewal contract configured")
+
+// USDPriceDecimals is the number of decimals in the USD price of Ether
+// returned by ETHUSDPrice
+const USDPriceDecimals = 8
+
+// baseRegistrarAddress obtains the address of the .eth base registrar
+func (c *Client) baseRegistrarAddress(ctx context.Context) (common.Address, error) {
+	if c.baseRegistrar != (common.Address{}) {
+		return c.baseRegistrar, nil
+	}
+	return c.Owner(ctx, "eth")
+}
+
+// baseRegistrarContract binds the .eth base registrar
+func (c *Client) baseRegistrarContract(ctx context.Context) (*bind.BoundContract, error) {
+	address, err := c.baseRegistrarAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.contract(address, baseRegistrarABI), nil
+}
+
+// controllerContract binds the .eth registrar controller
+func (c *Client) controllerContract() (*bind.BoundContract, error) {
+	if c.controller == (common.Address{}) {
+		return nil, ErrNoController
+	}
+	return c.contract(c.controller, controllerABI), nil
+}
+
+// LabelID obtains the token ID of a name directly under .eth
+func LabelID(name string) (*big.Int, error) {
+	label := strings.TrimSuffix(name, ".eth")
+	if label == name || label == "" || strings.Contains(label, ".") {
+		return nil, errors.New("name must be directly under .eth")
+	}
+	labelHash := LabelHash(label)
+	return new(big.Int).SetBytes(labelHash[:]), nil
+}
+
+// Expiry obtains the time at which the registration of a name directly
+// under .eth expires; it is zero if the name has never been registered
+func (c *Client) Expiry(ctx context.Context, name string) (time.Time, error) {
+	id, err := LabelID(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	baseRegistrar, err := c.baseRegistrarContract(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var expires *big.Int
+	if err = c.call(ctx, baseRegistrar, &expires, "nameExpires", id); err != nil || expires.Sign() == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(expires.Int64(), 0), nil
+}
+
+// Registrant obtains the registrant of a name directly under .eth
+func (c *Client) Registrant(ctx context.Context, name string) (common.Address, error) {
+	id, err := LabelID(name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return c.TokenRegistrant(ctx, id)
+}
+
+// TokenRegistrant obtains the registrant of a name directly under .eth from
+// its token ID in the base registrar, as returned by LabelID.  This fails
+// for names whose registration has expired
+func (c *Client) TokenRegistrant(ctx context.Context, id *big.Int) (registrant common.Address, err error) {
+	baseRegistrar, err := c.baseRegistrarContract(ctx)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, baseRegistrar, &registrant, "ownerOf", id)
+	return
+}
+
+// Available returns true if a label can be registered under .eth
+func (c *Client) Available(ctx context.Context, label string) (available bool, err error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, controller, &available, "available", label)
+	return
+}
+
+// RentPrice obtains the price in Wei of registering or renewing a label
+// under .eth for a duration, split in to the base price and any premium
+// for a recently expired name
+func (c *Client) RentPrice(ctx context.Context, label string, duration time.Duration) (base *big.Int, premium *big.Int, err error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return
+	}
+	var price struct {
+		Base    *big.Int
+		Premium *big.Int
+	}
+	err = c.call(ctx, controller, &price, "rentPrice", label, durationSeconds(duration))
+	return price.Base, price.Premium, err
+}
+
+// Renew creates a transaction extending the registration of a label under
+// .eth by a duration.  The value of the signer must cover the rent price
+func (c *Client) Renew(ctx context.Context, signer *bind.TransactOpts, label string, duration time.Duration) (*types.Transaction, error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, controller, signer, "renew", label, durationSeconds(duration))
+}
+
+// RenewAll creates a transaction extending the registrations of a number of
+// labels under .eth by a duration through the bulk renewal contract.  The
+// value of the signer must cover the total rent price
+func (c *Client) RenewAll(ctx context.Context, signer *bind.TransactOpts, labels []string, duration time.Duration) (*types.Transaction, error) {
+	if c.bulkRenewal == (common.Address{}) {
+		return nil, ErrNoBulkRenewal
+	}
+	bulkRenewal := c.contract(c.bulkRenewal, bulkRenewalABI)
+	return transact(ctx, bulkRenewal, signer, "renewAll", labels, durationSeconds(duration))
+}
+
+// ETHUSDPrice obtains the price of 1 Ether in USD, scaled by
+// USDPriceDecimals, from the oracle used by the registrar controller to set
+// its prices
+func (c *Client) ETHUSDPrice(ctx context.Context) (*big.Int, error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return nil, err
+	}
+	var oracle, usdOracle common.Address
+	if err = c.call(ctx, controller, &oracle, "prices"); err != nil {
+		return nil, err
+	}
+	if err = c.call(ctx, c.contract(oracle, priceOracleABI), &usdOracle, "usdOracle"); err != nil {
+		return nil, err
+	}
+	var price *big.Int
+	err = c.call(ctx, c.contract(usdOracle, usdOracleABI), &price, "latestAnswer")
+	return price, err
+}
+
+// SetRegistrant creates a transaction transferring the registration of a
+// name directly under .eth, which is its token in the base registrar, from
+// the signer to a new registrant.  This does not change the owner of the
+// name in the registry
+func (c *Client) SetRegistrant(ctx context.Context, signer *bind.TransactOpts, name string, registrant common.Address) (*types.Transaction, error) {
+	id, err := LabelID(name)
+	if err != nil {
+		return nil, err
+	}
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	baseRegistrar, err := c.baseRegistrarContract(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, baseRegistrar, signer, "safeTransferFrom", signer.From, registrant, id)
+}
+
+// MakeCommitment creates the commitment to register a label under .eth for
+// an owner, with no resolver or records
+func (c *Client) MakeCommitment(ctx context.Context, label string, owner common.Address, duration time.Duration, secret [32]byte) (commitment [32]byte, err error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, controller, &commitment, "makeCommitment", label, owner, durationSeconds(duration), secret, common.Address{}, [][]byte{}, false, uint16(0))
+	return
+}
+
+// CommitmentTime obtains the time at which a commitment was made; it is zero
+// if the commitment has not been made
+func (c *Client) CommitmentTime(ctx context.Context, commitment [32]byte) (time.Time, error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return time.Time{}, err
+	}
+	var timestamp *big.Int
+	if err = c.call(ctx, controller, &timestamp, "commitments", commitment); err != nil || timestamp.Sign() == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(timestamp.Int64(), 0), nil
+}
+
+// CommitmentAges obtains the minimum and maximum ages of a commitment for it
+// to be used to register a label
+func (c *Client) CommitmentAges(ctx context.Context) (min time.Duration, max time.Duration, err error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return
+	}
+	var minAge, maxAge *big.Int
+	if err = c.call(ctx, controller, &minAge, "minCommitmentAge"); err != nil {
+		return
+	}
+	if err = c.call(ctx, controller, &maxAge, "maxCommitmentAge"); err != nil {
+		return
+	}
+	return time.Duration(minAge.Int64()) * time.Second, time.Duration(maxAge.Int64()) * time.Second, nil
+}
+
+// Commit creates a transaction committing to register a label, which is
+// the first step of registration
+func (c *Client) Commit(ctx context.Context, signer *bind.TransactOpts, commitment [32]byte) (*types.Transaction, error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, controller, signer, "commit", commitment)
+}
+
+// Register creates a transaction registering a label under .eth for which a
+// commitment was made with MakeCommitment and Commit.  The value of the
+// signer must cover the rent price
+func (c *Client) Register(ctx context.Context, signer *bind.TransactOpts, label string, owner common.Address, duration time.Duration, secret [32]byte) (*types.Transaction, error) {
+	controller, err := c.controllerContract()
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, controller, signer, "register", label, owner, durationSeconds(duration), secret, common.Address{}, [][]byte{}, false, uint16(0))
+}
+
+// durationSeconds returns a duration as a whole number of seconds
+func durationSeconds(duration time.Duration) *big.Int {
+	return big.NewInt(int64(duration / time.Second))
+}
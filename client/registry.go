@@ -0,0 +1,110 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoLegacyRegistry is returned by operations on the legacy registry when
+// the client has none
+var ErrNoLegacyRegistry = errors.New("no legacy registry configured")
+
+// Record is the record of a name in a registry
+type Record struct {
+	// Owner is the owner of the name
+	Owner common.Address
+	// Resolver is the address of the resolver of the name
+	Resolver common.Address
+	// TTL is the time in seconds for which the record can be cached
+	TTL uint64
+}
+
+// Owner obtains the owner of a name in the registry
+func (c *Client) Owner(ctx context.Context, name string) (owner common.Address, err error) {
+	registry := c.contract(c.registry, registryABI)
+	err = c.call(ctx, registry, &owner, "owner", NameHash(name))
+	return
+}
+
+// Resolver obtains the address of the resolver of a name in the registry
+func (c *Client) Resolver(ctx context.Context, name string) (resolver common.Address, err error) {
+	registry := c.contract(c.registry, registryABI)
+	err = c.call(ctx, registry, &resolver, "resolver", NameHash(name))
+	return
+}
+
+// CreateSubname creates a transaction creating a subname of a parent in the
+// registry, or changing the owner of an existing one.  The resolver is only
+// set if it is not zero.  The signer must be the owner of the parent in the
+// registry; subnames of wrapped names are created with CreateWrappedSubname
+func (c *Client) CreateSubname(ctx context.Context, signer *bind.TransactOpts, parent string, label string, owner common.Address, resolver common.Address) (*types.Transaction, error) {
+	registry := c.contract(c.registry, registryABI)
+	if resolver == (common.Address{}) {
+		return transact(ctx, registry, signer, "setSubnodeOwner", NameHash(parent), LabelHash(label), owner)
+	}
+	return transact(ctx, registry, signer, "setSubnodeRecord", NameHash(parent), LabelHash(label), owner, resolver, uint64(0))
+}
+
+// RecordExists returns true if a name has a record in the registry, which
+// for names created before the 2020 migration is only the case once they
+// have been migrated from the legacy registry
+func (c *Client) RecordExists(ctx context.Context, name string) (exists bool, err error) {
+	registry := c.contract(c.registry, registryABI)
+	err = c.call(ctx, registry, &exists, "recordExists", NameHash(name))
+	return
+}
+
+// Record obtains the record of a name in the registry
+func (c *Client) Record(ctx context.Context, name string) (*Record, error) {
+	return c.registryRecord(ctx, c.registry, name)
+}
+
+// LegacyRecord obtains the record of a name in the legacy registry
+func (c *Client) LegacyRecord(ctx context.Context, name string) (*Record, error) {
+	if c.legacy == (common.Address{}) {
+		return nil, ErrNoLegacyRegistry
+	}
+	return c.registryRecord(ctx, c.legacy, name)
+}
+
+// registryRecord obtains the record of a name in the registry at an address
+func (c *Client) registryRecord(ctx context.Context, address common.Address, name string) (*Record, error) {
+	registry := c.contract(address, registryABI)
+	node := NameHash(name)
+	record := &Record{}
+	if err := c.call(ctx, registry, &record.Owner, "owner", node); err != nil {
+		return nil, err
+	}
+	if err := c.call(ctx, registry, &record.Resolver, "resolver", node); err != nil {
+		return nil, err
+	}
+	if err := c.call(ctx, registry, &record.TTL, "ttl", node); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// SetSubnameRecord creates a transaction setting the whole record of a
+// subname of a parent in the registry, creating the subname if it does not
+// exist.  The signer must be the owner of the parent in the registry
+func (c *Client) SetSubnameRecord(ctx context.Context, signer *bind.TransactOpts, parent string, label string, record *Record) (*types.Transaction, error) {
+	registry := c.contract(c.registry, registryABI)
+	return transact(ctx, registry, signer, "setSubnodeRecord", NameHash(parent), LabelHash(label), record.Owner, record.Resolver, record.TTL)
+}
@@ -0,0 +1,252 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoResolver is returned when a name has no resolver
+var ErrNoResolver = errors.New("no resolver")
+
+// resolverContract binds the resolver of a name with the given ABI
+func (c *Client) resolverContract(ctx context.Context, name string, parsed abi.ABI) (*bind.BoundContract, error) {
+	resolver, err := c.Resolver(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if resolver == (common.Address{}) {
+		return nil, ErrNoResolver
+	}
+	return c.contract(resolver, parsed), nil
+}
+
+// Address obtains the Ether address of a name from its resolver
+func (c *Client) Address(ctx context.Context, name string) (address common.Address, err error) {
+	resolver, err := c.resolverContract(ctx, name, addrResolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &address, "addr", NameHash(name))
+	return
+}
+
+// SetAddress creates a transaction setting the Ether address of a name
+func (c *Client) SetAddress(ctx context.Context, signer *bind.TransactOpts, name string, address common.Address) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, addrResolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setAddr", NameHash(name), address)
+}
+
+// Name obtains the name in the reverse record of an address; it is empty if
+// the record is not set
+func (c *Client) Name(ctx context.Context, address common.Address) (name string, err error) {
+	reverse := strings.ToLower(address.Hex()[2:]) + ".addr.reverse"
+	resolver, err := c.resolverContract(ctx, reverse, nameResolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &name, "name", NameHash(reverse))
+	return
+}
+
+// Text obtains the value of a text record of a name; it is empty if the
+// record is not set
+func (c *Client) Text(ctx context.Context, name string, key string) (value string, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &value, "text", NameHash(name), key)
+	return
+}
+
+// SetText creates a transaction setting the value of a text record of a
+// name; an empty value removes the record
+func (c *Client) SetText(ctx context.Context, signer *bind.TransactOpts, name string, key string, value string) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setText", NameHash(name), key, value)
+}
+
+// Contenthash obtains the binary content hash of a name; it is empty if the
+// content hash is not set
+func (c *Client) Contenthash(ctx context.Context, name string) (hash []byte, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &hash, "contenthash", NameHash(name))
+	return
+}
+
+// SetContenthash creates a transaction setting the binary content hash of a name
+func (c *Client) SetContenthash(ctx context.Context, signer *bind.TransactOpts, name string, hash []byte) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setContenthash", NameHash(name), hash)
+}
+
+// InterfaceImplementer obtains the address of the contract implementing an
+// interface for a name (EIP-1844); it is zero if there is none
+func (c *Client) InterfaceImplementer(ctx context.Context, name string, interfaceID [4]byte) (implementer common.Address, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &implementer, "interfaceImplementer", NameHash(name), interfaceID)
+	return
+}
+
+// SetInterface creates a transaction setting the address of the contract
+// implementing an interface for a name; a zero address removes the record
+func (c *Client) SetInterface(ctx context.Context, signer *bind.TransactOpts, name string, interfaceID [4]byte, implementer common.Address) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setInterface", NameHash(name), interfaceID, implementer)
+}
+
+// SupportsInterface returns true if the resolver at an address supports an
+// EIP-165 interface
+func (c *Client) SupportsInterface(ctx context.Context, resolver common.Address, interfaceID [4]byte) (supported bool, err error) {
+	err = c.call(ctx, c.contract(resolver, resolverABI), &supported, "supportsInterface", interfaceID)
+	return
+}
+
+// MultiAddress obtains the binary address of a name for an ENSIP-11 coin
+// type; it is empty if the address is not set
+func (c *Client) MultiAddress(ctx context.Context, name string, coinType uint64) (address []byte, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	err = c.call(ctx, resolver, &address, "addr", NameHash(name), new(big.Int).SetUint64(coinType))
+	return
+}
+
+// SetMultiAddress creates a transaction setting the binary address of a name
+// for an ENSIP-11 coin type; an empty address removes the record
+func (c *Client) SetMultiAddress(ctx context.Context, signer *bind.TransactOpts, name string, coinType uint64, address []byte) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setAddr", NameHash(name), new(big.Int).SetUint64(coinType), address)
+}
+
+// ABI obtains the ABI of a name with one of the requested content types,
+// which are a bitfield, returning the content type found along with the
+// data; the content type is zero if there is none
+func (c *Client) ABI(ctx context.Context, name string, contentTypes uint64) (contentType uint64, data []byte, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	var out []interface{}
+	if err = resolver.Call(c.callOpts(ctx), &out, "ABI", NameHash(name), new(big.Int).SetUint64(contentTypes)); err != nil {
+		return
+	}
+	if len(out) != 2 {
+		return 0, nil, errors.New("unexpected values returned")
+	}
+	contentType = abi.ConvertType(out[0], new(big.Int)).(*big.Int).Uint64()
+	data = *abi.ConvertType(out[1], new([]byte)).(*[]byte)
+	return
+}
+
+// SetABI creates a transaction setting the ABI of a name with a content
+// type; empty data removes the record
+func (c *Client) SetABI(ctx context.Context, signer *bind.TransactOpts, name string, contentType uint64, data []byte) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setABI", NameHash(name), new(big.Int).SetUint64(contentType), data)
+}
+
+// Pubkey obtains the secp256k1 public key of a name; both coordinates are
+// zero if it is not set
+func (c *Client) Pubkey(ctx context.Context, name string) (x [32]byte, y [32]byte, err error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return
+	}
+	var out []interface{}
+	if err = resolver.Call(c.callOpts(ctx), &out, "pubkey", NameHash(name)); err != nil {
+		return
+	}
+	if len(out) != 2 {
+		err = errors.New("unexpected values returned")
+		return
+	}
+	x = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	y = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	return
+}
+
+// SetPubkey creates a transaction setting the secp256k1 public key of a name
+func (c *Client) SetPubkey(ctx context.Context, signer *bind.TransactOpts, name string, x [32]byte, y [32]byte) (*types.Transaction, error) {
+	resolver, err := c.resolverContract(ctx, name, resolverABI)
+	if err != nil {
+		return nil, err
+	}
+	return transact(ctx, resolver, signer, "setPubkey", NameHash(name), x, y)
+}
+
+// EncodeRecordCall encodes a call of a record function of ResolverABI, for
+// use with SetRecord, Multicall and MulticallWithNodeCheck
+func EncodeRecordCall(method string, params ...interface{}) ([]byte, error) {
+	return resolverABI.Pack(method, params...)
+}
+
+// SetRecord creates a transaction sending a single encoded record call to
+// the resolver at an address
+func (c *Client) SetRecord(ctx context.Context, signer *bind.TransactOpts, resolver common.Address, call []byte) (*types.Transaction, error) {
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	opts := *signer
+	opts.Context = ctx
+	return c.contract(resolver, resolverABI).RawTransact(&opts, call)
+}
+
+// Multicall creates a transaction setting a number of records in a single
+// transaction to the resolver at an address, which must support multicall.
+// Each call is an encoded record call
+func (c *Client) Multicall(ctx context.Context, signer *bind.TransactOpts, resolver common.Address, calls [][]byte) (*types.Transaction, error) {
+	return transact(ctx, c.contract(resolver, resolverABI), signer, "multicall", calls)
+}
+
+// MulticallWithNodeCheck creates a transaction setting a number of records
+// of a name as Multicall does, with the resolver rejecting any call for
+// another name
+func (c *Client) MulticallWithNodeCheck(ctx context.Context, signer *bind.TransactOpts, resolver common.Address, name string, calls [][]byte) (*types.Transaction, error) {
+	return transact(ctx, c.contract(resolver, resolverABI), signer, "multicallWithNodeCheck", NameHash(name), calls)
+}
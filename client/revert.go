@@ -0,0 +1,86 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertData obtains the revert data from the error of a failed call, if present
+func RevertData(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// RevertReason obtains the reason for a reverted call from the revert data
+// returned by the node, decoding Error(string) and Panic(uint256).  Custom
+// errors are returned as their selector.  It returns an empty string if the
+// node did not return any revert data
+func RevertReason(err error) string {
+	data := RevertData(err)
+	if len(data) < 4 {
+		return ""
+	}
+	reason, unpackErr := abi.UnpackRevert(data)
+	if unpackErr != nil {
+		return fmt.Sprintf("custom error %s", hexutil.Encode(data[:4]))
+	}
+	return reason
+}
+
+// WithRevertReason adds the revert reason to the error from a call, if it is
+// not already present
+func WithRevertReason(err error) error {
+	if err == nil {
+		return nil
+	}
+	reason := RevertReason(err)
+	if reason == "" || strings.Contains(err.Error(), reason) {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, reason)
+}
+
+// RevertReasonTransactor is a transactor that adds revert reasons to the
+// errors from the gas estimates carried out when creating transactions
+type RevertReasonTransactor struct {
+	*ethclient.Client
+}
+
+// EstimateGas estimates the gas used by a call, adding the revert reason to
+// the error if the call reverts
+func (t *RevertReasonTransactor) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := t.Client.EstimateGas(ctx, msg)
+	return gas, WithRevertReason(err)
+}
@@ -0,0 +1,280 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNoAddress is returned when a name resolves but has no address
+var ErrNoAddress = errors.New("no address")
+
+// extendedResolverJSON is the ABI for ENSIP-10 wildcard resolution
+const extendedResolverJSON = `[
+{"constant":true,"inputs":[{"name":"name","type":"bytes"},{"name":"data","type":"bytes"}],"name":"resolve","outputs":[{"name":"","type":"bytes"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"type":"function"}
+]`
+
+// offchainLookupJSON is the ABI of the EIP-3668 OffchainLookup error
+const offchainLookupJSON = `[
+{"inputs":[{"name":"sender","type":"address"},{"name":"urls","type":"string[]"},{"name":"callData","type":"bytes"},{"name":"callbackFunction","type":"bytes4"},{"name":"extraData","type":"bytes"}],"name":"OffchainLookup","type":"error"}
+]`
+
+var extendedResolverABI = mustParseABI(extendedResolverJSON)
+var offchainLookupABI = mustParseABI(offchainLookupJSON)
+
+var bytesType, _ = abi.NewType("bytes", "", nil)
+
+// extendedResolverInterface is the EIP-165 interface ID of ENSIP-10 resolvers
+var extendedResolverInterface = [4]byte{0x90, 0x61, 0xb9, 0x23}
+
+// maxOffchainLookups is the maximum number of offchain lookups followed for a single call
+const maxOffchainLookups = 4
+
+// FindResolver finds the resolver for a name, walking up the name's parents
+// as required by ENSIP-10.  It returns the resolver and true if the resolver
+// is for the name itself rather than a parent, or ErrNoResolver if neither
+// the name nor any of its parents has a resolver
+func (c *Client) FindResolver(ctx context.Context, name string) (resolver common.Address, exact bool, err error) {
+	for current := name; current != ""; {
+		if resolver, err = c.Resolver(ctx, current); err != nil {
+			return common.Address{}, false, err
+		}
+		if resolver != (common.Address{}) {
+			return resolver, current == name, nil
+		}
+		dot := strings.Index(current, ".")
+		if dot == -1 {
+			break
+		}
+		current = current[dot+1:]
+	}
+	return common.Address{}, false, ErrNoResolver
+}
+
+// Resolve calls a resolver function for a name, given as its encoded call,
+// returning the encoded result.  If the resolver found by FindResolver
+// supports ENSIP-10 the call is made through resolve(), following EIP-3668
+// offchain lookups, and the URL of the gateway that answered the last
+// lookup is returned; otherwise the call is made directly on the resolver,
+// which must be that of the name itself
+func (c *Client) Resolve(ctx context.Context, name string, data []byte) (result []byte, gateway string, err error) {
+	resolver, exact, err := c.FindResolver(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	var extended bool
+	if err = c.call(ctx, c.contract(resolver, resolverABI), &extended, "supportsInterface", extendedResolverInterface); err != nil || !extended {
+		if !exact {
+			return nil, "", ErrNoResolver
+		}
+		return c.ccipCall(ctx, resolver, data)
+	}
+
+	dnsName, err := DNSEncode(name)
+	if err != nil {
+		return nil, "", err
+	}
+	call, err := extendedResolverABI.Pack("resolve", dnsName, data)
+	if err != nil {
+		return nil, "", err
+	}
+	output, gateway, err := c.ccipCall(ctx, resolver, call)
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := extendedResolverABI.Unpack("resolve", output)
+	if err != nil {
+		return nil, "", err
+	}
+	return values[0].([]byte), gateway, nil
+}
+
+// ResolveAddress resolves the Ether address of a name through Resolve,
+// returning ErrNoAddress if the name has no address
+func (c *Client) ResolveAddress(ctx context.Context, name string) (address common.Address, gateway string, err error) {
+	call, err := extendedResolverABI.Pack("addr", NameHash(name))
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	result, gateway, err := c.Resolve(ctx, name, call)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	values, err := extendedResolverABI.Unpack("addr", result)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	address = values[0].(common.Address)
+	if address == (common.Address{}) {
+		return common.Address{}, "", ErrNoAddress
+	}
+	return address, gateway, nil
+}
+
+// ResolveText resolves a text record of a name through Resolve
+func (c *Client) ResolveText(ctx context.Context, name string, key string) (value string, gateway string, err error) {
+	call, err := resolverABI.Pack("text", NameHash(name), key)
+	if err != nil {
+		return "", "", err
+	}
+	result, gateway, err := c.Resolve(ctx, name, call)
+	if err != nil {
+		return "", "", err
+	}
+	values, err := resolverABI.Unpack("text", result)
+	if err != nil {
+		return "", "", err
+	}
+	return values[0].(string), gateway, nil
+}
+
+// ccipCall calls a contract, following EIP-3668 offchain lookups if the
+// contract reverts with OffchainLookup.  It returns the output of the call
+// and the URL of the gateway that answered the last offchain lookup, or an
+// empty string if the call was answered onchain
+func (c *Client) ccipCall(ctx context.Context, to common.Address, data []byte) ([]byte, string, error) {
+	gateway := ""
+	for lookups := 0; ; lookups++ {
+		output, err := c.backend.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, c.blockNumber)
+		if err == nil {
+			return output, gateway, nil
+		}
+		revertData := RevertData(err)
+		offchainLookup := offchainLookupABI.Errors["OffchainLookup"]
+		if len(revertData) < 4 || !bytes.Equal(revertData[:4], offchainLookup.ID[:4]) {
+			return nil, "", err
+		}
+		if lookups == maxOffchainLookups {
+			return nil, "", errors.New("too many offchain lookups")
+		}
+
+		values, err := offchainLookup.Inputs.Unpack(revertData[4:])
+		if err != nil {
+			return nil, "", err
+		}
+		sender := values[0].(common.Address)
+		urls := values[1].([]string)
+		callData := values[2].([]byte)
+		callbackFunction := values[3].([4]byte)
+		extraData := values[4].([]byte)
+		if sender != to {
+			return nil, "", errors.New("offchain lookup sender does not match contract")
+		}
+
+		var response []byte
+		response, gateway, err = c.ccipFetch(ctx, urls, sender, callData)
+		if err != nil {
+			return nil, "", err
+		}
+		callbackArgs, err := abi.Arguments{{Type: bytesType}, {Type: bytesType}}.Pack(response, extraData)
+		if err != nil {
+			return nil, "", err
+		}
+		data = append(callbackFunction[:], callbackArgs...)
+	}
+}
+
+// ccipFetch fetches the response to an offchain lookup from the first gateway
+// that answers, returning the response and the gateway's URL
+func (c *Client) ccipFetch(ctx context.Context, urls []string, sender common.Address, callData []byte) ([]byte, string, error) {
+	senderHex := strings.ToLower(sender.Hex())
+	dataHex := hexutil.Encode(callData)
+	httpClient := &http.Client{Timeout: c.ccipTimeout}
+	err := errors.New("no gateway URLs")
+	for _, gateway := range urls {
+		if !c.ccipAllowed(gateway) {
+			err = fmt.Errorf("gateway %s is not allowed", gateway)
+			continue
+		}
+		var req *http.Request
+		target := strings.Replace(strings.Replace(gateway, "{sender}", senderHex, -1), "{data}", dataHex, -1)
+		if strings.Contains(gateway, "{data}") {
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		} else {
+			body, _ := json.Marshal(map[string]string{"data": dataHex, "sender": senderHex})
+			req, err = http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+		}
+		if err != nil {
+			continue
+		}
+		var resp *http.Response
+		resp, err = httpClient.Do(req)
+		if err != nil {
+			continue
+		}
+		var response []byte
+		response, err = ccipResponse(resp)
+		if err == nil {
+			return response, gateway, nil
+		}
+		// Only try further gateways on server errors
+		if resp.StatusCode < 500 {
+			return nil, "", err
+		}
+	}
+	return nil, "", err
+}
+
+// ccipAllowed returns true if the client allows a gateway URL
+func (c *Client) ccipAllowed(gateway string) bool {
+	if len(c.ccipAllow) == 0 {
+		return true
+	}
+	u, err := url.Parse(gateway)
+	if err != nil {
+		return false
+	}
+	for _, host := range c.ccipAllow {
+		if strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// ccipResponse decodes the response from a gateway
+func ccipResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gateway returned %s", resp.Status)
+	}
+	var response struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	return hexutil.Decode(response.Data)
+}
@@ -14,40 +14,149 @@
 package cmd
 
 import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"strings"
 
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
 )
 
+// EIP-205 ABI content types
+const (
+	abiContentTypeJSON = 1
+	abiContentTypeZlib = 2
+	abiContentTypeCBOR = 4
+	abiContentTypeURI  = 8
+)
+
+// abiContentTypes maps content type names to their values
+var abiContentTypes = map[string]uint64{
+	"json": abiContentTypeJSON,
+	"zlib": abiContentTypeZlib,
+	"cbor": abiContentTypeCBOR,
+	"uri":  abiContentTypeURI,
+}
+
+var abiContentTypeStr string
+
+type abiResult struct {
+	Name        string `json:"name"`
+	NameHash    string `json:"namehash"`
+	ContentType string `json:"contenttype"`
+	ABI         string `json:"abi"`
+}
+
 // abiCmd represents the abi command
 var abiCmd = &cobra.Command{
 	Use:   "abi",
 	Short: "Obtain the ABI associated with an ENS name",
 	Long: `Obtain the ABI associated with a name registered with the Ethereum Name Service (ENS).  For example:
 
-	ens abi enstest.eth
+    ens abi enstest.eth
 
-In quiet mode this will return 0 if the name has an ABI, otherwise 1.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Obtain the resolver for this name
-		resolverAddress, err := ens.Resolver(registryContract, args[0])
-		cli.ErrCheck(err, quiet, "No resolver for that name")
+By default any content type is accepted; a specific content type (json, zlib, cbor or uri) can be requested with --content-type.  Compressed ABIs are decompressed, and CBOR ABIs are printed as hex.
 
-		// Set the address to which we resolve
-		resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+In quiet mode this will return 0 if the name has an ABI, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		contentTypes := uint64(abiContentTypeJSON | abiContentTypeZlib | abiContentTypeCBOR | abiContentTypeURI)
+		if abiContentTypeStr != "" {
+			var exists bool
+			contentTypes, exists = abiContentTypes[strings.ToLower(abiContentTypeStr)]
+			assert(exists, exitBadInput, "Unknown content type")
+		}
 
 		// Fetch the ABI
-		abi, err := ens.Abi(resolverContract, args[0])
-		cli.ErrCheck(err, quiet, "Failed to obtain ABI")
+		contentType, data, err := ensClient.ABI(commandCtx, args[0], contentTypes)
+		errCheck(err, quiet, "Failed to obtain ABI")
+		assert(contentType != 0, exitNotFound, "No ABI for that name")
+		abi, err := decodeABI(contentType, data)
+		errCheck(err, quiet, "Failed to decode ABI")
 		if !quiet {
-			fmt.Println(string(abi))
+			if jsonOutput {
+				outputJSON(&abiResult{
+					Name:        args[0],
+					NameHash:    nameHashHex(args[0]),
+					ContentType: abiContentTypeName(contentType),
+					ABI:         abi,
+				})
+			} else {
+				fmt.Println(abi)
+			}
 		}
 	},
 }
 
+// abiContentTypeName returns the name of an ABI content type
+func abiContentTypeName(contentType uint64) string {
+	for name, value := range abiContentTypes {
+		if value == contentType {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d", contentType)
+}
+
+// encodeABI encodes an ABI for storage with the given content type
+func encodeABI(contentType uint64, abi string) ([]byte, error) {
+	switch contentType {
+	case abiContentTypeJSON:
+		if !json.Valid([]byte(abi)) {
+			return nil, errors.New("ABI is not valid JSON")
+		}
+		return []byte(abi), nil
+	case abiContentTypeZlib:
+		if !json.Valid([]byte(abi)) {
+			return nil, errors.New("ABI is not valid JSON")
+		}
+		var buf bytes.Buffer
+		writer := zlib.NewWriter(&buf)
+		if _, err := writer.Write([]byte(abi)); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case abiContentTypeCBOR:
+		if !strings.HasPrefix(abi, "0x") {
+			return nil, errors.New("CBOR ABI must be supplied as 0x-prefixed hex")
+		}
+		return hex.DecodeString(abi[2:])
+	case abiContentTypeURI:
+		return []byte(abi), nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %d", contentType)
+	}
+}
+
+// decodeABI decodes an ABI stored with the given content type
+func decodeABI(contentType uint64, data []byte) (string, error) {
+	switch contentType {
+	case abiContentTypeJSON, abiContentTypeURI:
+		return string(data), nil
+	case abiContentTypeZlib:
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+		abi, err := ioutil.ReadAll(reader)
+		return string(abi), err
+	case abiContentTypeCBOR:
+		return hexutil.Encode(data), nil
+	default:
+		return "", fmt.Errorf("unsupported content type %d", contentType)
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(abiCmd)
+
+	abiCmd.Flags().StringVar(&abiContentTypeStr, "content-type", "", "Content type of the ABI to obtain (json, zlib, cbor or uri)")
 }
@@ -15,11 +15,8 @@ package cmd
 
 import (
 	"bytes"
-	"fmt"
-	"math/big"
+	"strings"
 
-	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -27,59 +24,60 @@ import (
 
 var abiSetAbi string
 var abiSetCompressed bool
+var abiSetContentTypeStr string
 
-// abiSetCmd represents the address set command
+// abiSetCmd represents the abi set command
 var abiSetCmd = &cobra.Command{
 	Use:   "set",
-	Short: "Set the address of an ENS name",
-	Long: `Set the address of a name registered with the Ethereum Name Service (ENS).  For example:
+	Short: "Set the ABI of an ENS name",
+	Long: `Set the ABI of a name registered with the Ethereum Name Service (ENS).  For example:
 
-    ens address set --address=0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1 --passphrase="my secret passphrase" enstest.eth
+    ens abi set --abi='[{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"}]' --passphrase="my secret passphrase" enstest.eth
+
+The content type can be selected with --content-type:
+
+  - json: uncompressed JSON (the default)
+  - zlib: JSON compressed with zlib
+  - cbor: CBOR, supplied as 0x-prefixed hex
+  - uri: a URI from which the ABI can be fetched
 
 The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to set the address is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to set the ABI is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
+		contentType := uint64(abiContentTypeJSON)
+		if abiSetCompressed {
+			contentType = abiContentTypeZlib
+		}
+		if abiSetContentTypeStr != "" {
+			var exists bool
+			contentType, exists = abiContentTypes[strings.ToLower(abiSetContentTypeStr)]
+			assert(exists, exitBadInput, "Unknown content type")
+		}
+		data, err := encodeABI(contentType, abiSetAbi)
+		errCheck(badInput(err), quiet, "Invalid ABI")
+
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Owned"), quiet, "Domain not in a suitable state to set an address")
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set an ABI")
 
 		// Fetch the owner of the name
-		owner, err := registryContract.Owner(nil, ens.NameHash(args[0]))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
 
 		// Fetch the wallet and account for the owner
 		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Obtain the resolver for this name
-		resolverAddress, err := ens.Resolver(registryContract, args[0])
-		cli.ErrCheck(err, quiet, "No resolver for that name")
-
-		// Set the address to which we resolve
-		resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
-		session := ens.CreateResolverSession(chainID, &wallet, account, passphrase, resolverContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
-
-		var contentType = big.NewInt(1)
-		if abiSetCompressed {
-			contentType = big.NewInt(2)
-		}
-		tx, err := ens.SetAbi(session, args[0], abiSetAbi, contentType)
-		cli.ErrCheck(err, quiet, "Failed to set ABI for that name")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0],
-			"abi":       abiSetAbi}).Info("ABI set")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		tx, err := ensClient.SetABI(commandCtx, opts, args[0], contentType, data)
+		errCheck(err, quiet, "Failed to set ABI for that name")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"contenttype": abiContentTypeName(contentType),
+			"abi":         abiSetAbi}, "ABI set")
 	},
 }
 
@@ -87,6 +85,7 @@ func init() {
 	abiCmd.AddCommand(abiSetCmd)
 
 	abiSetCmd.Flags().StringVarP(&abiSetAbi, "abi", "a", "", "ABI to associate with the name")
-	abiSetCmd.Flags().BoolVarP(&abiSetCompressed, "compressed", "2", false, "Store the ABI in compressed form (content type 2)")
+	abiSetCmd.Flags().BoolVarP(&abiSetCompressed, "compressed", "2", false, "Store the ABI in compressed form (equivalent to --content-type=zlib)")
+	abiSetCmd.Flags().StringVar(&abiSetContentTypeStr, "content-type", "", "Content type of the ABI (json, zlib, cbor or uri)")
 	addTransactionFlags(abiSetCmd, "Passphrase for the account that owns the name")
 }
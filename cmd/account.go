@@ -0,0 +1,45 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// accountCmd represents the account command
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Manage accounts in the keystore",
+	Long:  `List and import the accounts in the keystore used to send Ethereum Name Service transactions.`,
+}
+
+// accountKeystorePath returns the keystore directory given by --keystore or
+// $ETH_KEYSTORE, or the default keystore directory if neither is set
+func accountKeystorePath() (string, error) {
+	if dir := keystorePath(); dir != "" {
+		return dir, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ethereum", "keystore"), nil
+}
+
+func init() {
+	RootCmd.AddCommand(accountCmd)
+}
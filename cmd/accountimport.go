@@ -0,0 +1,111 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var accountImportKeyFile string
+
+type accountImportResult struct {
+	Address string `json:"address"`
+	Path    string `json:"path"`
+}
+
+// accountImportCmd represents the account import command
+var accountImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a private key into the keystore",
+	Long: `Import a private key into the keystore, encrypting it with a passphrase.  For example:
+
+    ens account import --key=/path/to/keyfile
+
+The key file contains the private key in hex.  The passphrase is given by --passphrase, --passphrase-file or --passphrase-stdin, or is prompted for twice if a terminal is attached.  The keystore is given by --keystore or $ETH_KEYSTORE, defaulting to ~/.ethereum/keystore, and is created if it does not exist.
+
+In quiet mode this will return 0 if the key is imported, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(accountImportKeyFile != "", exitBadInput, "Key file is required")
+		data, err := ioutil.ReadFile(accountImportKeyFile)
+		errCheck(err, quiet, "Failed to read key file")
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+		errCheck(badInput(err), quiet, "Invalid private key")
+		address := crypto.PubkeyToAddress(key.PublicKey)
+
+		if passphrase == "" {
+			err = newPassphrase(address)
+			errCheck(err, quiet, "Failed to obtain passphrase")
+		}
+		assert(passphrase != "", exitBadInput, "Passphrase is required")
+
+		dir, err := accountKeystorePath()
+		errCheck(err, quiet, "Failed to obtain keystore directory")
+		err = os.MkdirAll(dir, 0700)
+		errCheck(err, quiet, "Failed to create keystore")
+		ks, err := openKeystore(dir)
+		errCheck(err, quiet, "Failed to open keystore")
+		account, err := ks.ImportECDSA(key, passphrase)
+		errCheck(err, quiet, "Failed to import key")
+
+		if !quiet {
+			if jsonOutput {
+				outputJSON(&accountImportResult{
+					Address: account.Address.Hex(),
+					Path:    account.URL.Path,
+				})
+			} else {
+				fmt.Println("Imported", account.Address.Hex(), "to", account.URL.Path)
+			}
+		}
+	},
+}
+
+// newPassphrase obtains the passphrase for a new account, prompting for it
+// twice to confirm it if it is not read from a file or stdin
+func newPassphrase(address common.Address) error {
+	if passphraseFile != "" || passphraseStdin || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return resolvePassphrase(address)
+	}
+	if err := resolvePassphrase(address); err != nil {
+		return err
+	}
+	first := passphrase
+	fmt.Fprintf(os.Stderr, "Repeat passphrase for %s: ", address.Hex())
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+	if string(data) != first {
+		return fmt.Errorf("passphrases do not match")
+	}
+	return nil
+}
+
+func init() {
+	accountCmd.AddCommand(accountImportCmd)
+
+	accountImportCmd.Flags().StringVar(&accountImportKeyFile, "key", "", "File containing the private key to import, in hex")
+	accountImportCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase with which to encrypt the key")
+	accountImportCmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File containing the passphrase")
+	accountImportCmd.Flags().BoolVar(&passphraseStdin, "passphrase-stdin", false, "Read the passphrase from the first line of stdin")
+}
@@ -0,0 +1,67 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type accountListResult struct {
+	Address string `json:"address"`
+	Path    string `json:"path"`
+}
+
+// accountListCmd represents the account list command
+var accountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the accounts in the keystore",
+	Long: `List the addresses of the accounts in the keystore and the files that hold them.  For example:
+
+    ens account list --keystore=/path/to/keystore
+
+The keystore is given by --keystore or $ETH_KEYSTORE, defaulting to ~/.ethereum/keystore.
+
+In quiet mode this will return 0 if the keystore contains accounts, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := accountKeystorePath()
+		errCheck(err, quiet, "Failed to obtain keystore directory")
+		ks, err := openKeystore(dir)
+		errCheck(err, quiet, "Failed to open keystore")
+
+		keystoreAccounts := ks.Accounts()
+		if quiet {
+			if len(keystoreAccounts) == 0 {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+		for _, account := range keystoreAccounts {
+			if jsonOutput {
+				outputJSON(&accountListResult{
+					Address: account.Address.Hex(),
+					Path:    account.URL.Path,
+				})
+			} else {
+				fmt.Printf("%s\t%s\n", account.Address.Hex(), account.URL.Path)
+			}
+		}
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountListCmd)
+}
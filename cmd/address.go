@@ -16,29 +16,147 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var addressCoinStr string
+var addressProfile bool
+var addressShowSource bool
+
+// profileTextKeys are the text records shown with --profile
+var profileTextKeys = []string{
+	"avatar",
+	"url",
+	"com.twitter",
+	"description",
+}
+
+type addressResult struct {
+	Name     string            `json:"name"`
+	NameHash string            `json:"namehash"`
+	CoinType uint64            `json:"cointype,omitempty"`
+	Address  string            `json:"address"`
+	Profile  map[string]string `json:"profile,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Gateway  string            `json:"gateway,omitempty"`
+}
+
 // addressCmd represents the address command
 var addressCmd = &cobra.Command{
 	Use:   "address",
 	Short: "Obtain the address of an ENS name",
 	Long: `Obtain the address of a name registered with the Ethereum Name Service (ENS).  For example:
 
-	ens address enstest.eth
+    ens address enstest.eth
+
+Wildcard (ENSIP-10) and offchain (EIP-3668) resolution is supported.
+
+Addresses for other coins can be obtained with --coin, giving either the SLIP-44 symbol or coin type.  For example:
+
+    ens address --coin=BTC enstest.eth
+
+Where the address came from can be shown with --show-source: onchain, a gateway answering an offchain lookup, or an L2 if the gateway is listed under the network's l2gateways in the config file.  The gateway's URL is also shown with --verbosity=info or --json.
+
+The name's profile, being its avatar, URL, Twitter handle and description text records, can be shown along with its address with --profile.
+
+What a name resolved to at an earlier block can be obtained with --block, giving a block number or one of latest, safe, finalized or earliest.  For example:
+
+    ens address --block=14000000 enstest.eth
 
-In quiet mode this will return 0 if the name resolves correctly, otherwise 1.`,
+In quiet mode this will return 0 if the name resolves correctly, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		address, err := ens.Resolve(client, args[0])
-		cli.ErrCheck(err, quiet, "Failed to obtain address")
+		if addressCoinStr != "" {
+			coinType, err := parseCoinType(addressCoinStr)
+			errCheck(badInput(err), quiet, "Invalid coin")
+			if coinType != ethCoinType {
+				assert(!addressProfile, exitBadInput, "--profile cannot be used with --coin")
+				assert(!addressShowSource, exitBadInput, "--show-source cannot be used with --coin")
+				addressForCoin(args[0], coinType)
+				return
+			}
+		}
+
+		address, source, err := resolveAddressWithSource(args[0])
+		errCheck(err, quiet, "Failed to obtain address")
+		var profile map[string]string
+		if addressProfile {
+			profile, err = nameProfile(args[0])
+			errCheck(err, quiet, "Failed to obtain profile")
+		}
 		if !quiet {
-			fmt.Println(address.Hex())
+			if jsonOutput {
+				result := &addressResult{
+					Name:     args[0],
+					NameHash: nameHashHex(args[0]),
+					Address:  address.Hex(),
+					Profile:  profile,
+				}
+				if addressShowSource {
+					result.Source = source.String()
+					result.Gateway = source.gateway
+				}
+				outputJSON(result)
+			} else {
+				fmt.Println(address.Hex())
+				if addressShowSource {
+					fmt.Println("Source:", source)
+					if source.gateway != "" && log.IsLevelEnabled(log.InfoLevel) {
+						fmt.Println("Gateway:", source.gateway)
+					}
+				}
+				for _, key := range profileTextKeys {
+					if value, exists := profile[key]; exists {
+						fmt.Printf("%s: %s\n", key, value)
+					}
+				}
+			}
 		}
 	},
 }
 
+// addressForCoin obtains and prints the address of a name for a coin other than Ethereum
+func addressForCoin(name string, coinType uint64) {
+	data, err := ensClient.MultiAddress(commandCtx, name, coinType)
+	errCheck(err, quiet, "Failed to obtain address")
+	assert(len(data) > 0, exitNotFound, "No address set for that coin")
+	address, err := decodeCoinAddress(coinType, data)
+	errCheck(err, quiet, "Failed to decode address")
+	if !quiet {
+		if jsonOutput {
+			outputJSON(&addressResult{
+				Name:     name,
+				NameHash: nameHashHex(name),
+				CoinType: coinType,
+				Address:  address,
+			})
+		} else {
+			fmt.Println(address)
+		}
+	}
+}
+
+// nameProfile obtains the profile text records that are set for a name,
+// supporting ENSIP-10 wildcard resolution and EIP-3668 offchain lookups
+func nameProfile(name string) (map[string]string, error) {
+	profile := make(map[string]string)
+	for _, key := range profileTextKeys {
+		value, _, err := ensClient.ResolveText(commandCtx, name, key)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			profile[key] = value
+		}
+	}
+	return profile, nil
+}
+
 func init() {
 	RootCmd.AddCommand(addressCmd)
+
+	addressCmd.Flags().StringVar(&addressCoinStr, "coin", "", "Coin for which to obtain the address, as a SLIP-44 symbol or coin type (default ETH)")
+	addressCmd.Flags().BoolVar(&addressProfile, "profile", false, "Also show the avatar, URL, Twitter handle and description of the name")
+	addressCmd.Flags().BoolVar(&addressShowSource, "show-source", false, "Also show whether the address came from onchain, an L2 or a gateway")
+	addBlockFlag(addressCmd)
 }
@@ -0,0 +1,73 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// addressClearCmd represents the address clear command
+var addressClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the address of an ENS name",
+	Long: `Clear the address of a name registered with the Ethereum Name Service (ENS), so that it no longer resolves.  For example:
+
+    ens address clear --passphrase="my secret passphrase" enstest.eth
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to clear the address is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Ensure that the name is in a suitable state
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to clear an address")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		// Obtain the resolver for this name
+		resolverAddress, err := ens.Resolver(registryContract, args[0])
+		errCheck(err, quiet, "No resolver for that name")
+		err = checkResolverInterface(resolverAddress, "addr")
+		errCheck(err, quiet, "Resolver cannot hold addresses")
+
+		// Clear the address to which we resolve
+		resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
+		errCheck(err, quiet, "Failed to obtain resolver contract")
+		session := ens.CreateResolverSession(chainID, &wallet, account, passphrase, resolverContract, gasPrice)
+		configureTransactOpts(&session.TransactOpts)
+
+		tx, err := ens.SetResolution(session, args[0], &ens.UnknownAddress)
+		errCheck(err, quiet, "Failed to clear resolution for that name")
+		handleTransaction(tx, log.Fields{"name": args[0]}, "Address clear")
+	},
+}
+
+func init() {
+	addressCmd.AddCommand(addressClearCmd)
+
+	addTransactionFlags(addressClearCmd, "Passphrase for the account that owns the name")
+}
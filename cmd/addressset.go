@@ -15,10 +15,17 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
+	"strings"
 
-	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
@@ -26,6 +33,10 @@ import (
 )
 
 var addressSetAddressStr string
+var addressSetFromFile string
+var addressSetStrict bool
+var addressSetCoinStr string
+var addressSetReverse bool
 
 // addressSetCmd represents the address set command
 var addressSetCmd = &cobra.Command{
@@ -35,58 +46,302 @@ var addressSetCmd = &cobra.Command{
 
     ens address set --address=0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1 --passphrase="my secret passphrase" enstest.eth
 
+Addresses for other coins can be set with --coin, giving either the SLIP-44 symbol or coin type.  For example:
+
+    ens address set --coin=BTC --address=1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2 --passphrase="my secret passphrase" enstest.eth
+
+Addresses for multiple names can be set from a CSV file with one name,address pair per line:
+
+    ens address set --from-file=addresses.csv --passphrase="my secret passphrase"
+
+The address can also be given the name as its primary name with --set-reverse, which sends a second transaction to set the reverse record.  This requires the address to be that of the owner of the name, as only the address itself can set its reverse record.
+
+Each line is sent as a separate transaction with consecutive nonces.  With --wait each transaction is waited for before the next line is sent, and a transaction that is reverted or not mined in time counts as a failure.  A line that fails is reported and the remaining lines are still processed, unless --strict is supplied.
+
+Progress through a file can be recorded with --state-file, so that a batch that is interrupted can be re-run to resume where it left off:
+
+    ens address set --from-file=addresses.csv --state-file=addresses.state --passphrase="my secret passphrase"
+
+A line recorded in the state file is skipped if the name already resolves to the address on-chain, or if the transaction sent for it is still pending; otherwise it is sent again.  Lines are recorded as sent, and with --wait as confirmed once their transaction is mined.
+
 The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to set the address is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to set the address is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).  For a file this will return 0 only if all transactions are sent successfully.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if addressSetReverse {
+			assert(addressSetFromFile == "", exitBadInput, "--set-reverse cannot be used with --from-file")
+			assert(addressSetCoinStr == "", exitBadInput, "--set-reverse cannot be used with --coin")
+			assert(outputTxFile == "", exitBadInput, "--output-tx cannot be used with --set-reverse")
+		}
+		if addressSetFromFile != "" {
+			assert(addressSetCoinStr == "", exitBadInput, "--coin cannot be used with --from-file")
+			assert(outputTxFile == "", exitBadInput, "--output-tx cannot be used with --from-file")
+			addressSetBatch()
+			return
+		}
+		assert(batchStateFile == "", exitBadInput, "--state-file can only be used with --from-file")
+		coinType := uint64(ethCoinType)
+		if addressSetCoinStr != "" {
+			var err error
+			coinType, err = parseCoinType(addressSetCoinStr)
+			errCheck(badInput(err), quiet, "Invalid coin")
+		}
+
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Owned"), quiet, "Domain not in a suitable state to set an address")
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set an address")
 
 		// Fetch the owner of the name
-		owner, err := registryContract.Owner(nil, ens.NameHash(args[0]))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
 
 		// Fetch the wallet and account for the owner
 		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
 		// Obtain the resolver for this name
 		resolverAddress, err := ens.Resolver(registryContract, args[0])
-		cli.ErrCheck(err, quiet, "No resolver for that name")
+		errCheck(err, quiet, "No resolver for that name")
+
+		if coinType != ethCoinType {
+			err = checkResolverInterface(resolverAddress, "multicoin addr")
+			errCheck(err, quiet, "Resolver cannot hold addresses for other coins")
+			addressSetForCoin(&wallet, account, gasPrice, args[0], coinType)
+			return
+		}
+
+		err = checkResolverInterface(resolverAddress, "addr")
+		errCheck(err, quiet, "Resolver cannot hold addresses")
 
 		// Obtain the address to which we resolve
-		resolutionAddress, err := ens.Resolve(client, addressSetAddressStr)
-		cli.ErrCheck(err, quiet, "Invalid address")
+		resolutionAddress, err := resolveAddress(addressSetAddressStr)
+		errCheck(badInput(err), quiet, "Invalid address")
+		// The reverse record can only be set by the address itself
+		assert(!addressSetReverse || resolutionAddress == owner, exitBadInput, "--set-reverse requires the address to be that of the owner of the name")
 
 		// Set the address to which we resolve
 		resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+		errCheck(err, quiet, "Failed to obtain resolver contract")
 		session := ens.CreateResolverSession(chainID, &wallet, account, passphrase, resolverContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		configureTransactOpts(&session.TransactOpts)
 
 		tx, err := ens.SetResolution(session, args[0], &resolutionAddress)
-		cli.ErrCheck(err, quiet, "Failed to set resolution for that name")
+		errCheck(err, quiet, "Failed to set resolution for that name")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address": resolutionAddress.Hex()}, "Address set")
+
+		if addressSetReverse {
+			addressSetReverseName(&wallet, account, gasPrice, args[0])
+		}
+	},
+}
+
+// addressSetReverseName sets the reverse record of the owner of a name to
+// the name.  It follows the transaction setting the address, so takes the
+// next nonce
+func addressSetReverseName(wallet *accounts.Wallet, account *accounts.Account, gasPrice *big.Int, name string) {
+	reverseRegistrar, err := reverseRegistrarContract()
+	errCheck(err, quiet, "Failed to obtain reverse registrar contract")
+	session := ens.CreateReverseRegistrarSession(chainID, wallet, account, passphrase, reverseRegistrar, gasPrice)
+	configureTransactOpts(&session.TransactOpts)
+
+	tx, err := ens.SetName(session, name)
+	errCheck(err, quiet, "Failed to set name for that address")
+	handleTransaction(tx, log.Fields{"address": account.Address.Hex(),
+		"name": name}, "Name set")
+}
+
+// addressSetForCoin sets the address of a name for a coin other than Ethereum
+func addressSetForCoin(wallet *accounts.Wallet, account *accounts.Account, gasPrice *big.Int, name string, coinType uint64) {
+	address, err := encodeCoinAddress(coinType, addressSetAddressStr)
+	errCheck(badInput(err), quiet, "Invalid address for that coin")
+	opts := generateTransactOpts(wallet, account, passphrase, gasPrice)
+	tx, err := ensClient.SetMultiAddress(commandCtx, opts, name, coinType, address)
+	errCheck(err, quiet, "Failed to set address for that name")
+	handleTransaction(tx, log.Fields{"name": name,
+		"cointype": coinType,
+		"address":  addressSetAddressStr}, "Address set")
+}
+
+// addressSetOwner holds the account details for an owner of names in a batch
+type addressSetOwner struct {
+	wallet  accounts.Wallet
+	account *accounts.Account
+}
+
+// addressSetBatch sets the addresses of the names listed in a CSV file
+func addressSetBatch() {
+	f, err := os.Open(addressSetFromFile)
+	errCheck(err, quiet, "Failed to open file")
+	defer f.Close()
+
+	gasPrice, err := gasPriceFlag()
+	errCheck(badInput(err), quiet, "Invalid gas price")
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	state, err := readBatchState()
+	errCheck(err, quiet, "Failed to read state file")
+	owners := make(map[common.Address]*addressSetOwner)
+	succeeded := 0
+	skipped := 0
+	failed := 0
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			if len(record) != 2 {
+				err = errors.New("expected name,address")
+			} else {
+				var tx *types.Transaction
+				var name string
+				var reason string
+				addressStr := strings.TrimSpace(record[1])
+				name, err = cleanName(record[0])
+				if err == nil {
+					name, err = normalizeName(expandName(name))
+				}
+				if err == nil {
+					reason = addressSetCompleted(state[name], name, addressStr)
+					if reason != "" {
+						skipped++
+						if !quiet {
+							fmt.Printf("%s: %s\n", name, reason)
+						}
+						continue
+					}
+					tx, err = addressSetBatchEntry(owners, name, addressStr, gasPrice)
+				}
+				if err == nil {
+					if !quiet {
+						fmt.Printf("%s: transaction ID is %s\n", name, tx.Hash().Hex())
+					}
+					err = recordBatchState(name, addressStr, batchEntrySent, tx)
+					errCheck(err, quiet, "Failed to record state")
+					if wait && sendsTransactions() {
+						if err = awaitTransaction(tx); err == nil {
+							err = recordBatchState(name, addressStr, batchEntryConfirmed, tx)
+							errCheck(err, quiet, "Failed to record state")
+						}
+					}
+				}
+				if err == nil {
+					succeeded++
+					continue
+				}
+			}
+		}
+		failed++
 		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
+			fmt.Fprintf(os.Stderr, "Line %d: %v\n", line, err)
+		}
+		cli.Assert(!addressSetStrict, quiet, "Stopping due to failure")
+	}
+	if !quiet {
+		if batchStateFile != "" {
+			fmt.Printf("%d succeeded, %d skipped, %d failed\n", succeeded, skipped, failed)
+		} else {
+			fmt.Printf("%d succeeded, %d failed\n", succeeded, failed)
 		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0],
-			"address":   resolutionAddress.Hex()}).Info("Address set")
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
 
-	},
+// addressSetCompleted returns the reason for skipping a line of a batch that
+// was recorded in the state file, or an empty string if it is to be sent.
+// The on-chain address is checked rather than trusting the recorded transaction
+func addressSetCompleted(entry *batchStateEntry, name string, addressStr string) string {
+	if entry == nil || entry.Value != addressStr {
+		return ""
+	}
+	resolutionAddress, err := resolveAddress(addressStr)
+	if err != nil {
+		return ""
+	}
+	current, err := resolveAddress(name)
+	if err == nil && current == resolutionAddress {
+		if entry.Status != batchEntryConfirmed {
+			err = recordBatchState(name, addressStr, batchEntryConfirmed, nil)
+			errCheck(err, quiet, "Failed to record state")
+		}
+		return "address already set"
+	}
+	if entry.Status == batchEntrySent && batchTransactionPending(entry) {
+		return fmt.Sprintf("transaction %s is still pending", entry.TransactionID)
+	}
+	return ""
+}
+
+// addressSetBatchEntry sends a transaction to set the address of a single name in a batch
+func addressSetBatchEntry(owners map[common.Address]*addressSetOwner, name string, addressStr string, gasPrice *big.Int) (*types.Transaction, error) {
+	if !inState(name, "Owned") {
+		return nil, errors.New("domain not in a suitable state to set an address")
+	}
+	ownerAddress, err := registryContract.Owner(callOpts(), ens.NameHash(name))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Compare(ownerAddress.Bytes(), ens.UnknownAddress.Bytes()) == 0 {
+		return nil, errors.New("owner is not set")
+	}
+	resolutionAddress, err := resolveAddress(addressStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s", addressStr)
+	}
+	resolverAddress, err := ens.Resolver(registryContract, name)
+	if err != nil {
+		return nil, errors.New("no resolver for that name")
+	}
+	if err := checkResolverInterface(resolverAddress, "addr"); err != nil {
+		return nil, err
+	}
+	resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Share account details between names with the same owner; each
+	// transaction takes the nonce following the last one sent by its owner
+	owner, exists := owners[ownerAddress]
+	if !exists {
+		wallet, account, err := obtainWalletAndAccount(ownerAddress, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		owner = &addressSetOwner{wallet: wallet, account: account}
+		owners[ownerAddress] = owner
+	}
+
+	session := ens.CreateResolverSession(chainID, &owner.wallet, owner.account, passphrase, resolverContract, gasPrice)
+	configureTransactOpts(&session.TransactOpts)
+	tx, err := ens.SetResolution(session, name, &resolutionAddress)
+	if err != nil {
+		return nil, err
+	}
+	// A failure is reported against the line rather than ending the batch
+	if err = submitTransaction(tx, log.Fields{"name": name,
+		"address": resolutionAddress.Hex()}, "Address set"); err != nil {
+		return nil, err
+	}
+	return tx, nil
 }
 
 func init() {
 	addressCmd.AddCommand(addressSetCmd)
 
-	addressSetCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase for the account that owns the name")
 	addressSetCmd.Flags().StringVarP(&addressSetAddressStr, "address", "a", "", "Address to set for the name")
-	addressSetCmd.Flags().StringVarP(&gasPriceStr, "gasprice", "g", "4 GWei", "Gas price for the transaction")
+	addressSetCmd.Flags().StringVar(&addressSetFromFile, "from-file", "", "CSV file of name,address pairs to set")
+	addressSetCmd.Flags().StringVar(&batchStateFile, "state-file", "", "File in which to record progress through --from-file, so that an interrupted batch can be resumed")
+	addressSetCmd.Flags().BoolVar(&addressSetStrict, "strict", false, "Stop at the first failure when setting addresses from a file")
+	addressSetCmd.Flags().StringVar(&addressSetCoinStr, "coin", "", "Coin for which to set the address, as a SLIP-44 symbol or coin type (default ETH)")
+	addressSetCmd.Flags().BoolVar(&addressSetReverse, "set-reverse", false, "Also set the name as the primary name of the address")
+	addTransactionFlags(addressSetCmd, "Passphrase for the account that owns the name")
 }
@@ -14,12 +14,7 @@
 package cmd
 
 import (
-	"fmt"
-	"math/big"
-
 	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -29,64 +24,56 @@ var auctionBidBidPriceStr string
 var auctionBidMaskPriceStr string
 var auctionBidSalt string
 
-// auctionBidCmd represents the auctionBid set command
+// auctionBidCmd represents the auction bid command
 var auctionBidCmd = &cobra.Command{
 	Use:   "bid",
-	Short: "Bid the auction for an ENS name",
-	Long: `Bid the auction for a name with the Ethereum Name Service (ENS).  For example:
+	Short: "Bid in the auction for an ENS name",
+	Long: `Place a sealed bid in a running auction for a name with the Ethereum Name Service (ENS).  For example:
+
+    ens auction bid --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" --bid="0.01 Ether" --salt="my memorable phrase" enstest.eth
+
+The auction must already have been started (see 'ens auction start').  The same address, bid and salt will be required to reveal the bid.  If no salt is supplied then a random salt is generated and shown; it is recorded in the bid journal along with the rest of the bid.
 
-    ens auction bid --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" --bid="0.01 Ether" enstest.eth
+The mask is the amount of Ether sent with the bid, which hides the value of the bid until it is revealed; the excess of the mask over the bid is refunded on reveal.  If no mask is supplied then a random mask is picked between the bid and --mask-max, which defaults to twice the bid.  A mask the same as the bid shows the value of the bid to anyone watching, and a mask below the bid is rejected.
 
 The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to place the bid is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to place the bid is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(auctionBidSalt != "", quiet, "Salt is required")
-		cli.Assert(auctionBidAddressStr != "", quiet, "Address from which to send the bid is required")
+		assert(auctionBidAddressStr != "", exitBadInput, "Address from which to send the bid is required")
 
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Bidding"), quiet, "Domain not in a suitable state to bid on an auction")
+		assert(inState(args[0], "Bidding"), exitBadInput, "Domain not in a suitable state to bid on an auction")
 
 		// Fetch the wallet and account for the owner
-		auctionBidAddress, err := ens.Resolve(client, auctionBidAddressStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain auction address")
+		auctionBidAddress, err := resolveAddress(auctionBidAddressStr)
+		errCheck(err, quiet, "Failed to obtain auction address")
 		wallet, account, err := obtainWalletAndAccount(auctionBidAddress, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+		errCheck(err, quiet, "Failed to obtain an account for the address")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
 		bidPrice, err := etherutils.StringToWei(auctionBidBidPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid bid price")
-		// Start the auction
-		bidMask, err := etherutils.StringToWei(auctionBidMaskPriceStr)
-		if err != nil {
-			bidMask = big.NewInt(0)
-			bidMask.Set(bidPrice)
-		} else if bidMask.Cmp(bidPrice) == -1 {
-			bidMask.Set(bidPrice)
-		}
-
-		session.TransactOpts.Value = bidMask
-		tx, err := ens.NewBid(session, args[0], &auctionBidAddress, *bidPrice, auctionBidSalt)
-		session.TransactOpts.Value = big.NewInt(0)
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0],
-			"address":   auctionBidAddress.Hex(),
-			"salt":      auctionBidSalt,
-			"bid":       bidPrice,
-			"mask":      bidMask}).Info("Auction bid")
+		errCheck(badInput(err), quiet, "Invalid bid price")
+		assert(bidPrice.Cmp(zero) > 0, exitBadInput, "Bid price must be greater than 0")
+		bidMask := obtainBidMask(auctionBidMaskPriceStr, bidPrice)
+
+		auctionBidSalt = obtainBidSalt(auctionBidSalt)
+		err = checkValueBalance(opts, bidMask)
+		errCheck(err, quiet, "Balance check failed")
+		opts.Value = bidMask
+		tx, err := ensClient.Bid(commandCtx, opts, args[0], auctionBidAddress, bidPrice, auctionBidSalt)
+		errCheck(err, quiet, "Failed to send transaction")
+		err = recordBid(args[0], auctionBidAddress, bidPrice, bidMask, auctionBidSalt, tx)
+		errCheck(err, quiet, "Failed to record bid in journal; the bid has not been sent")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address": auctionBidAddress.Hex(),
+			"salt":    auctionBidSalt,
+			"bid":     bidPrice,
+			"mask":    bidMask}, "Auction bid")
 	},
 }
 
@@ -95,7 +82,9 @@ func init() {
 
 	auctionBidCmd.Flags().StringVarP(&auctionBidAddressStr, "address", "a", "", "Address doing the bidding")
 	auctionBidCmd.Flags().StringVarP(&auctionBidBidPriceStr, "bid", "b", "0.01 Ether", "Bid price for the name")
-	auctionBidCmd.Flags().StringVarP(&auctionBidMaskPriceStr, "mask", "m", "", "Amount of Ether sent in the transaction (must be at least the bid)")
-	auctionBidCmd.Flags().StringVarP(&auctionBidSalt, "salt", "s", "", "Memorable phrase needed when revealing bid")
+	auctionBidCmd.Flags().StringVarP(&auctionBidMaskPriceStr, "mask", "m", "", "Amount of Ether sent in the transaction, which must be at least the bid (default a random amount above the bid)")
+	auctionBidCmd.Flags().StringVarP(&auctionBidSalt, "salt", "s", "", "Memorable phrase needed when revealing bid (default a random salt)")
 	addTransactionFlags(auctionBidCmd, "Passphrase for the account that owns the bidding address")
+	addBidMaskMaxFlag(auctionBidCmd)
+	addBidJournalFlag(auctionBidCmd)
 }
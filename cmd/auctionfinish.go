@@ -16,69 +16,68 @@ package cmd
 import (
 	"bytes"
 	"fmt"
-	"math/big"
+	"os"
 
-	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
-// auctionFinishCmd represents the auction reveal command
+var auctionFinishAddressStr string
+
+// auctionFinishCmd represents the auction finish command
 var auctionFinishCmd = &cobra.Command{
-	Use:   "finish",
-	Short: "Finish an auction for an ENS name",
+	Use:     "finish",
+	Aliases: []string{"finalize"},
+	Short:   "Finish an auction for an ENS name",
 	Long: `Finish an auction for a name with the Ethereum Name Service (ENS).  For example:
 
     ens auction finish --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" enstest.eth
 
+The address must be that of the winning bidder.  If it is not the highest revealed bidder a warning is shown; the registrar only allows the winning bidder to finish the auction, so the transaction will fail.
+
 The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to finish the auction is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to finish the auction is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Won"), quiet, "Domain not in a suitable state to finish the auction")
+		assert(inState(args[0], "Won"), exitBadInput, "Domain not in a suitable state to finish the auction")
 
 		// Fetch the owner of the name - must be 0 if this auction has not been finalised
-		owner, err := registryContract.Owner(nil, ens.NameHash(args[0]))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) == 0, quiet, "Auction already finished")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) == 0, exitBadInput, "Auction already finished")
 
-		_, deedAddress, _, _, _, err := ens.Entry(registrarContract, client, args[0])
-		cli.ErrCheck(err, quiet, "Cannot obtain information for that auction")
+		entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+		errCheck(err, quiet, "Cannot obtain information for that auction")
 
 		// Fetch the owner of the deed that won the address
 		// Deed
-		deedContract, err := ens.DeedContract(client, &deedAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain deed contract")
+		deedContract, err := ens.DeedContract(client, &entry.Deed)
+		errCheck(err, quiet, "Failed to obtain deed contract")
 		// Deed owner
-		deedOwner, err := deedContract.Owner(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
+		deedOwner, err := deedContract.Owner(callOpts())
+		errCheck(err, quiet, "Failed to obtain deed owner")
+		auctionFinishAddress, err := resolveAddress(auctionFinishAddressStr)
+		errCheck(err, quiet, "Failed to obtain auction address")
+		if bytes.Compare(auctionFinishAddress.Bytes(), deedOwner.Bytes()) != 0 && !quiet {
+			fmt.Fprintf(os.Stderr, "WARNING: %s is not the highest revealed bidder; the winning bidder is %s\n", auctionFinishAddress.Hex(), deedOwner.Hex())
+		}
 
-		// Fetch the wallet and account for the owner
-		wallet, account, err := obtainWalletAndAccount(deedOwner, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+		// Fetch the wallet and account for the address
+		wallet, account, err := obtainWalletAndAccount(auctionFinishAddress, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the address")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
 		// Finish the bid
-		tx, err := ens.FinishAuction(session, args[0])
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0]}).Info("Auction finish")
+		tx, err := ensClient.FinishAuction(commandCtx, opts, args[0])
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0]}, "Auction finish")
 
 	},
 }
@@ -86,5 +85,8 @@ In quiet mode this will return 0 if the transaction to finish the auction is sen
 func init() {
 	auctionCmd.AddCommand(auctionFinishCmd)
 
+	auctionFinishCmd.Flags().StringVarP(&auctionFinishAddressStr, "address", "a", "", "Address of the winning bidder")
+	auctionFinishCmd.MarkFlagRequired("address")
+
 	addTransactionFlags(auctionFinishCmd, "Passphrase for the account that owns the winning address")
 }
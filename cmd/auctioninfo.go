@@ -0,0 +1,140 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+)
+
+// revealPeriod is the length of the reveal period at the end of an auction
+var revealPeriod = time.Duration(48) * time.Hour
+
+type auctionInfoResult struct {
+	Name         string `json:"name"`
+	NameHash     string `json:"namehash"`
+	State        string `json:"state"`
+	Deed         string `json:"deed,omitempty"`
+	Value        string `json:"value,omitempty"`
+	HighestBid   string `json:"highestbid,omitempty"`
+	RevealDate   string `json:"revealdate,omitempty"`
+	FinalizeDate string `json:"finalizedate,omitempty"`
+	// Deadlines as unix timestamps, and the seconds until them by chain time
+	ChainTime         int64  `json:"chaintime,omitempty"`
+	RevealTimestamp   int64  `json:"revealtimestamp,omitempty"`
+	RevealIn          *int64 `json:"revealin,omitempty"`
+	FinalizeTimestamp int64  `json:"finalizetimestamp,omitempty"`
+	FinalizeIn        *int64 `json:"finalizein,omitempty"`
+}
+
+// auctionInfoCmd represents the auction info command
+var auctionInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Obtain information about an auction for an ENS name",
+	Long: `Obtain the state and timings of an auction for a name with the Ethereum Name Service (ENS).  For example:
+
+    ens auction info enstest.eth
+
+The time until bids can be revealed and until the auction can be finalized is worked out from the timestamp of the latest block rather than the local clock, as this is the time that the registrar uses.  JSON output includes the deadlines as unix timestamps, and the seconds until each of them (0 once passed).
+
+In quiet mode this will return 0 if the auction exists and is active, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+		errCheck(err, quiet, "Cannot obtain information for that auction")
+
+		if quiet {
+			if entry.State == "Bidding" || entry.State == "Revealing" {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		result := &auctionInfoResult{
+			Name:     args[0],
+			NameHash: nameHashHex(args[0]),
+			State:    entry.State,
+		}
+		if entry.State == "Available" || entry.State == "Forbidden" {
+			if jsonOutput {
+				outputJSON(result)
+			} else {
+				fmt.Printf("%-18s%s\n", "State:", entry.State)
+			}
+			return
+		}
+		revealDate := entry.RegistrationDate.Add(-revealPeriod)
+		now, err := chainTime()
+		errCheck(err, quiet, "Failed to obtain latest block")
+		if jsonOutput {
+			result.Deed = entry.Deed.Hex()
+			result.Value = entry.Value.String()
+			result.HighestBid = entry.HighestBid.String()
+			result.RevealDate = revealDate.UTC().Format(time.RFC3339)
+			result.FinalizeDate = entry.RegistrationDate.UTC().Format(time.RFC3339)
+			result.ChainTime = now.Unix()
+			result.RevealTimestamp = revealDate.Unix()
+			result.RevealIn = secondsUntil(now, revealDate)
+			result.FinalizeTimestamp = entry.RegistrationDate.Unix()
+			result.FinalizeIn = secondsUntil(now, entry.RegistrationDate)
+			outputJSON(result)
+			return
+		}
+
+		fmt.Printf("%-18s%s\n", "State:", entry.State)
+		fmt.Printf("%-18s%s\n", "Deed:", entry.Deed.Hex())
+		fmt.Printf("%-18s%s\n", "Value:", etherutils.WeiToString(entry.Value, true))
+		fmt.Printf("%-18s%s\n", "Highest bid:", etherutils.WeiToString(entry.HighestBid, true))
+		fmt.Printf("%-18s%s (%s)\n", "Reveal from:", revealDate.UTC().Format(time.RFC1123), revealDate.Local().Format(time.RFC1123))
+		fmt.Printf("%-18s%s\n", "Reveal in:", countdown(now, revealDate))
+		fmt.Printf("%-18s%s (%s)\n", "Finalize from:", entry.RegistrationDate.UTC().Format(time.RFC1123), entry.RegistrationDate.Local().Format(time.RFC1123))
+		fmt.Printf("%-18s%s\n", "Finalize in:", countdown(now, entry.RegistrationDate))
+	},
+}
+
+// secondsUntil returns the number of seconds from now until a deadline, or
+// 0 if it has passed
+func secondsUntil(now time.Time, deadline time.Time) *int64 {
+	var seconds int64
+	if deadline.After(now) {
+		seconds = int64(deadline.Sub(now) / time.Second)
+	}
+	return &seconds
+}
+
+// countdown describes the time from now until a deadline in days, hours
+// and minutes
+func countdown(now time.Time, deadline time.Time) string {
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return "now"
+	}
+	days := remaining / (24 * time.Hour)
+	hours := (remaining % (24 * time.Hour)) / time.Hour
+	minutes := (remaining % time.Hour) / time.Minute
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm %ds", minutes, (remaining%time.Minute)/time.Second)
+}
+
+func init() {
+	auctionCmd.AddCommand(auctionInfoCmd)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// auctionReleaseCmd represents the auction release command
+var auctionReleaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Release the deed of an ENS name won at auction",
+	Long: `Release the deed of a name won at auction with the Ethereum Name Service (ENS) auction registrar, recovering its deposit in full and giving up the name.  For example:
+
+    ens auction release --passphrase="my secret passphrase" enstest.eth
+
+Once released the name is no longer owned and can be registered by anyone.  The release must be confirmed by typing 'yes', or with --yes; in quiet mode --yes is required.
+
+The keystore for the owner of the deed must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to release the deed is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Auctions only exist for names directly under .eth")
+
+		entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+		errCheck(err, quiet, "Cannot obtain information for that name")
+		assert(entry.State == "Owned", exitBadInput, "Name is not owned through a deed")
+		deed, err := ens.DeedContract(client, &entry.Deed)
+		errCheck(err, quiet, "Failed to obtain deed contract")
+		owner, err := deed.Owner(callOpts())
+		errCheck(err, quiet, "Failed to obtain deed owner")
+		value, err := deed.Value(callOpts())
+		errCheck(err, quiet, "Failed to obtain deed value")
+		if !quiet {
+			fmt.Println("Deed owner:", owner.Hex())
+			fmt.Println("Recoverable:", etherutils.WeiToString(value, true))
+		}
+
+		// Releasing gives up the name, so is never done without the user agreeing
+		if sendsTransactions() && !assumeYes {
+			assert(!quiet, exitBadInput, "Releasing a deed requires --yes in quiet mode")
+			fmt.Fprintf(os.Stderr, "WARNING: releasing the deed gives up %s, which can then be registered by anyone\n", args[0])
+			fmt.Print("Type 'yes' to release the deed: ")
+			cli.Assert(readConfirmation(), quiet, "Deed not released")
+		}
+
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the deed")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.ReleaseDeed(commandCtx, opts, args[0])
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address":     owner.Hex(),
+			"recoverable": value}, "Auction release")
+	},
+}
+
+func init() {
+	auctionCmd.AddCommand(auctionReleaseCmd)
+
+	addTransactionFlags(auctionReleaseCmd, "Passphrase for the account that owns the deed")
+}
@@ -14,11 +14,7 @@
 package cmd
 
 import (
-	"fmt"
-	"math/big"
-
 	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -38,43 +34,40 @@ var auctionRevealCmd = &cobra.Command{
 
 The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to reveal the bid is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to reveal the bid is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(auctionRevealSalt != "", quiet, "Salt is required")
+		assert(auctionRevealSalt != "", exitBadInput, "Salt is required")
+		assert(auctionRevealAddressStr != "", exitBadInput, "Address from which the bid was sent is required")
 
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Revealing"), quiet, "Domain not in a suitable state to reveal a bid")
+		assert(inState(args[0], "Revealing"), exitBadInput, "Domain not in a suitable state to reveal a bid")
 
 		// Fetch the wallet and account for the address
-		auctionRevealAddress, err := ens.Resolve(client, auctionRevealAddressStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain auction address")
+		auctionRevealAddress, err := resolveAddress(auctionRevealAddressStr)
+		errCheck(err, quiet, "Failed to obtain auction address")
 		wallet, account, err := obtainWalletAndAccount(auctionRevealAddress, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+		errCheck(err, quiet, "Failed to obtain an account for the address")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
 		bidPrice, err := etherutils.StringToWei(auctionRevealBidPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid bid price")
+		errCheck(badInput(err), quiet, "Invalid bid price")
+
+		// Ensure that there is a sealed bid matching the details we have been given
+		sealedBidDeed, err := ensClient.SealedBid(commandCtx, args[0], auctionRevealAddress, bidPrice, auctionRevealSalt)
+		errCheck(err, quiet, "Failed to obtain sealed bid")
+		assert(sealedBidDeed != ens.UnknownAddress, exitNotFound, "No bid found for that address, bid and salt; check that they match those used when bidding")
 
 		// Reveal the bid
-		tx, err := ens.RevealBid(session, args[0], &auctionRevealAddress, *bidPrice, auctionRevealSalt)
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0],
-			"address":   auctionRevealAddress.Hex(),
-			"salt":      auctionRevealSalt,
-			"bid":       bidPrice}).Info("Auction reveal")
+		tx, err := ensClient.RevealBid(commandCtx, opts, args[0], auctionRevealAddress, bidPrice, auctionRevealSalt)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address": auctionRevealAddress.Hex(),
+			"salt":    auctionRevealSalt,
+			"bid":     bidPrice}, "Auction reveal")
 
 	},
 }
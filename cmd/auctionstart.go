@@ -17,10 +17,10 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -40,65 +40,64 @@ var auctionStartCmd = &cobra.Command{
 
     ens auction start --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" --bid="0.01 Ether" enstest.eth
 
+If a bid is placed and no salt is supplied with --salt then a random salt is generated and shown.  The salt is recorded in the bid journal along with the rest of the bid, but keep a note of it as it is needed to reveal the bid.
+
+If a bid is placed and no mask is supplied with --mask then a random mask is picked between the bid and --mask-max, which defaults to twice the bid, to hide the value of the bid; the excess of the mask over the bid is refunded when the bid is revealed.  A mask below the bid is rejected.
+
+Names must be at least 7 characters long excluding .eth, as the original auction registrar allows anyone to invalidate shorter names.  The registrar does not expose this minimum, so for a network whose auction registrar differs it can be given as auctionminnamelength in the config file.
+
 The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to start the auction is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to start the auction is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(auctionStartAddressStr != "", quiet, "Address from which to start the auction is required")
-		cli.Assert(len(args[0]) > 10, quiet, "Name must be at least 7 characters long")
-		cli.Assert(len(strings.Split(args[0], ".")) == 2, quiet, "Name must not contain . (except for ending in .eth)")
+		assert(auctionStartAddressStr != "", exitBadInput, "Address from which to start the auction is required")
+		assert(len(strings.Split(args[0], ".")) == 2, exitBadInput, "Name must not contain . (except for ending in .eth)")
+		label, err := ens.Domain(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+		minLength := auctionMinNameLength()
+		assert(utf8.RuneCountInString(label) >= minLength, exitBadInput, fmt.Sprintf("Name must be at least %d characters long (excluding .eth) to be auctioned", minLength))
 
 		// Ensure that the name is in a suitable state
-		cli.Assert(inState(args[0], "Available"), quiet, "Domain not in a suitable state to start an auction")
+		assert(inState(args[0], "Available"), exitBadInput, "Domain not in a suitable state to start an auction")
 
 		// Create the bid
 
 		// Fetch the wallet and account for the address
-		auctionStartAddress, err := ens.Resolve(client, auctionStartAddressStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain auction address")
+		auctionStartAddress, err := resolveAddress(auctionStartAddressStr)
+		errCheck(err, quiet, "Failed to obtain auction address")
 		wallet, account, err := obtainWalletAndAccount(auctionStartAddress, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain an account for the address")
+		errCheck(err, quiet, "Failed to obtain an account for the address")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
 		bidPrice, err := etherutils.StringToWei(auctionStartBidPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid bid price")
+		errCheck(badInput(err), quiet, "Invalid bid price")
 		// Start the auction
-		bidMask, err := etherutils.StringToWei(auctionStartMaskPriceStr)
-		if err != nil {
-			bidMask = big.NewInt(0)
-			bidMask.Set(bidPrice)
-		} else if bidMask.Cmp(bidPrice) == -1 {
-			bidMask.Set(bidPrice)
-		}
-
+		bidMask := big.NewInt(0)
 		var tx *types.Transaction
 		if bidPrice.Cmp(zero) == 0 {
-			tx, err = ens.StartAuction(session, args[0])
+			tx, err = ensClient.StartAuction(commandCtx, opts, args[0])
 		} else {
-			cli.Assert(auctionStartSalt != "", quiet, "Salt is required")
-			session.TransactOpts.Value = bidMask
-			tx, err = ens.StartAuctionAndBid(session, args[0], &auctionStartAddress, *bidPrice, auctionStartSalt, auctionStartDummies)
-			session.TransactOpts.Value = big.NewInt(0)
+			bidMask = obtainBidMask(auctionStartMaskPriceStr, bidPrice)
+			auctionStartSalt = obtainBidSalt(auctionStartSalt)
+			err = checkValueBalance(opts, bidMask)
+			errCheck(err, quiet, "Balance check failed")
+			opts.Value = bidMask
+			tx, err = ensClient.StartAuctionAndBid(commandCtx, opts, args[0], auctionStartAddress, bidPrice, auctionStartSalt, auctionStartDummies)
 		}
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
+		errCheck(err, quiet, "Failed to send transaction")
+		if bidPrice.Cmp(zero) != 0 {
+			err = recordBid(args[0], auctionStartAddress, bidPrice, bidMask, auctionStartSalt, tx)
+			errCheck(err, quiet, "Failed to record bid in journal; the bid has not been sent")
 		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"name":      args[0],
-			"networkid": chainID,
-			"address":   auctionStartAddress.Hex(),
-			"salt":      auctionStartSalt,
-			"bid":       bidPrice,
-			"mask":      bidMask}).Info("Auction start")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address": auctionStartAddress.Hex(),
+			"salt":    auctionStartSalt,
+			"bid":     bidPrice,
+			"mask":    bidMask}, "Auction start")
 	},
 }
 
@@ -107,9 +106,11 @@ func init() {
 
 	auctionStartCmd.Flags().StringVarP(&auctionStartAddressStr, "address", "a", "", "Address doing the bidding")
 	auctionStartCmd.Flags().StringVarP(&auctionStartBidPriceStr, "bid", "b", "0.01 Ether", "Bid price for the name. A 0-ether bid starts the auction without bidding")
-	auctionStartCmd.Flags().StringVarP(&auctionStartMaskPriceStr, "mask", "m", "", "Amount of Ether sent in the transaction (must be at least the bid)")
-	auctionStartCmd.Flags().StringVarP(&auctionStartSalt, "salt", "s", "", "Memorable phrase needed when revealing bid")
+	auctionStartCmd.Flags().StringVarP(&auctionStartMaskPriceStr, "mask", "m", "", "Amount of Ether sent in the transaction, which must be at least the bid (default a random amount above the bid)")
+	auctionStartCmd.Flags().StringVarP(&auctionStartSalt, "salt", "s", "", "Memorable phrase needed when revealing bid (default a random salt)")
 	auctionStartCmd.Flags().IntVarP(&auctionStartDummies, "dummies", "d", 3, "Number of dummy entries to hide the true name being bid")
 	addTransactionFlags(auctionStartCmd, "Passphrase for the account that owns the bidding address")
+	addBidMaskMaxFlag(auctionStartCmd)
+	addBidJournalFlag(auctionStartCmd)
 
 }
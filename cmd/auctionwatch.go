@@ -0,0 +1,198 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+const registrarEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"hash","type":"bytes32"},{"indexed":false,"name":"registrationDate","type":"uint256"}],"name":"AuctionStarted","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"hash","type":"bytes32"},{"indexed":true,"name":"bidder","type":"address"},{"indexed":false,"name":"deposit","type":"uint256"}],"name":"NewBid","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"hash","type":"bytes32"},{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"status","type":"uint8"}],"name":"BidRevealed","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"hash","type":"bytes32"},{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"value","type":"uint256"},{"indexed":false,"name":"registrationDate","type":"uint256"}],"name":"HashRegistered","type":"event"}
+]`
+
+var auctionWatchSealedBids bool
+
+type auctionWatchResult struct {
+	Name             string `json:"name,omitempty"`
+	Event            string `json:"event"`
+	Block            uint64 `json:"block"`
+	TransactionID    string `json:"transactionid"`
+	Account          string `json:"account,omitempty"`
+	Value            string `json:"value,omitempty"`
+	Status           *uint8 `json:"status,omitempty"`
+	RegistrationDate string `json:"registrationdate,omitempty"`
+}
+
+// auctionWatchCmd represents the auction watch command
+var auctionWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch events for an auction of an ENS name",
+	Long: `Watch auction events for a name with the Ethereum Name Service (ENS) as they occur.  For example:
+
+    ens auction watch --connection=wss://mainnet.example.com/ enstest.eth
+
+This requires a WebSocket or IPC connection.  Auction starts, bid reveals and registrations are shown for the name.  New bids are sealed so cannot be tied to a name; --sealed-bids shows all new bids on the registrar.
+
+Events are printed until interrupted with Ctrl-C.
+
+In quiet mode this will return 0 if the events can be watched, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		label, err := ens.Domain(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+		labelHash := ens.LabelHash(label)
+
+		parsed, err := abi.JSON(strings.NewReader(registrarEventsABI))
+		errCheck(err, quiet, "Failed to parse registrar events")
+		registrarAddress, err := registryContract.Owner(callOpts(), ens.NameHash("eth"))
+		errCheck(err, quiet, "Failed to obtain registrar address")
+
+		queries := []ethereum.FilterQuery{{
+			Addresses: []common.Address{registrarAddress},
+			Topics: [][]common.Hash{{
+				parsed.Events["AuctionStarted"].ID,
+				parsed.Events["BidRevealed"].ID,
+				parsed.Events["HashRegistered"].ID,
+			}, {labelHash}},
+		}}
+		if auctionWatchSealedBids {
+			queries = append(queries, ethereum.FilterQuery{
+				Addresses: []common.Address{registrarAddress},
+				Topics:    [][]common.Hash{{parsed.Events["NewBid"].ID}},
+			})
+		}
+
+		// Failures of the subscriptions are passed back to be reported here
+		// rather than exiting from their goroutines
+		logs := make(chan types.Log)
+		subErrs := make(chan error, len(queries))
+		subs := make([]ethereum.Subscription, 0, len(queries))
+		unsubscribe := func() {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+		}
+		for _, query := range queries {
+			sub, err := client.SubscribeFilterLogs(commandCtx, query, logs)
+			if err != nil {
+				unsubscribe()
+			}
+			errCheck(err, quiet, "Failed to subscribe to registrar events; a WebSocket or IPC connection is required")
+			subs = append(subs, sub)
+			go func(sub ethereum.Subscription) {
+				subErrs <- <-sub.Err()
+			}(sub)
+		}
+		if quiet {
+			unsubscribe()
+			os.Exit(0)
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		if !jsonOutput {
+			fmt.Println("Watching for events; press Ctrl-C to stop")
+		}
+		for {
+			select {
+			case <-interrupt:
+				unsubscribe()
+				return
+			case err := <-subErrs:
+				unsubscribe()
+				errCheck(networkFailure(err), quiet, "Subscription to registrar events failed")
+			case log := <-logs:
+				printAuctionEvent(&parsed, args[0], log)
+			}
+		}
+	},
+}
+
+// printAuctionEvent prints a registrar event
+func printAuctionEvent(parsed *abi.ABI, name string, log types.Log) {
+	event, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return
+	}
+	values := make(map[string]interface{})
+	if err = parsed.UnpackIntoMap(values, event.Name, log.Data); err != nil {
+		return
+	}
+
+	result := &auctionWatchResult{
+		Event:         event.Name,
+		Block:         log.BlockNumber,
+		TransactionID: log.TxHash.Hex(),
+	}
+	if event.Name != "NewBid" {
+		result.Name = name
+	}
+	if len(log.Topics) > 2 {
+		result.Account = common.BytesToAddress(log.Topics[2].Bytes()).Hex()
+	}
+	for _, key := range []string{"value", "deposit"} {
+		if value, exists := values[key]; exists {
+			result.Value = value.(*big.Int).String()
+		}
+	}
+	if status, exists := values["status"]; exists {
+		s := status.(uint8)
+		result.Status = &s
+	}
+	if registrationDate, exists := values["registrationDate"]; exists {
+		result.RegistrationDate = time.Unix(registrationDate.(*big.Int).Int64(), 0).UTC().Format(time.RFC3339)
+	}
+
+	if jsonOutput {
+		outputJSON(result)
+		return
+	}
+	msg := fmt.Sprintf("Block %d: %s", result.Block, result.Event)
+	if result.Account != "" {
+		msg += " by " + result.Account
+	}
+	if value, exists := values["value"]; exists {
+		msg += " of " + etherutils.WeiToString(value.(*big.Int), true)
+	} else if deposit, exists := values["deposit"]; exists {
+		msg += " with deposit " + etherutils.WeiToString(deposit.(*big.Int), true)
+	}
+	if result.Status != nil {
+		msg += fmt.Sprintf(" (status %d)", *result.Status)
+	}
+	if result.RegistrationDate != "" {
+		msg += "; registration date " + result.RegistrationDate
+	}
+	fmt.Println(msg)
+}
+
+func init() {
+	auctionCmd.AddCommand(auctionWatchCmd)
+
+	auctionWatchCmd.Flags().BoolVar(&auctionWatchSealedBids, "sealed-bids", false, "Also show all new sealed bids on the registrar")
+}
@@ -0,0 +1,120 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// cancelBidDelay is the time after a bid is placed at which the registrar
+// allows it to be cancelled if it has not been revealed
+var cancelBidDelay = time.Duration(19*24) * time.Hour
+
+var auctionWithdrawAddressStr string
+var auctionWithdrawBidPriceStr string
+var auctionWithdrawSalt string
+
+// auctionWithdrawCmd represents the auction withdraw command
+var auctionWithdrawCmd = &cobra.Command{
+	Use:   "withdraw",
+	Short: "Recover the deposit of a bid in an auction for an ENS name",
+	Long: `Recover what can be recovered of the deposit of a bid in an auction for a name with the Ethereum Name Service (ENS) auction registrar.  For example:
+
+    ens auction withdraw --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" --bid="0.01 Ether" --salt="my memorable phrase" enstest.eth
+
+Bids that lost an auction after being revealed are refunded when they are revealed, less 0.5%, so have nothing to recover.  Bids that were not revealed in time can be cancelled 19 days after they were placed, recovering 0.5% of the deposit; the rest is burned.  The deposit of a bid that won an auction can only be recovered by releasing the deed with 'ens auction release', which gives up the name.
+
+The amount that can be recovered is shown before anything is sent.
+
+The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to recover the deposit is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(auctionWithdrawAddressStr != "", exitBadInput, "Address from which the bid was sent is required")
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Auctions only exist for names directly under .eth")
+
+		bidder, err := resolveAddress(auctionWithdrawAddressStr)
+		errCheck(err, quiet, "Failed to obtain bidding address")
+		entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+		errCheck(err, quiet, "Cannot obtain information for that name")
+
+		// Work out what, if anything, can be recovered
+		var withdraw func(opts *bind.TransactOpts) (*types.Transaction, error)
+		var recoverable *big.Int
+		if auctionWithdrawSalt != "" {
+			bidPrice, err := etherutils.StringToWei(auctionWithdrawBidPriceStr)
+			errCheck(badInput(err), quiet, "Invalid bid price")
+			sealedBidDeed, err := ensClient.SealedBid(commandCtx, args[0], bidder, bidPrice, auctionWithdrawSalt)
+			errCheck(err, quiet, "Failed to obtain sealed bid")
+			if sealedBidDeed != ens.UnknownAddress {
+				assert(entry.State != "Revealing", exitBadInput, "Bid can still be revealed; use 'ens auction reveal' to recover more of the deposit")
+				deed, err := ens.DeedContract(client, &sealedBidDeed)
+				errCheck(err, quiet, "Failed to obtain deed contract")
+				creationDate, err := deed.CreationDate(callOpts())
+				errCheck(err, quiet, "Failed to obtain bid date")
+				cancellable := time.Unix(creationDate.Int64(), 0).Add(cancelBidDelay)
+				assert(time.Now().After(cancellable), exitBadInput, fmt.Sprintf("Unrevealed bid cannot be cancelled until %s", cancellable.UTC().Format(time.RFC1123)))
+				value, err := deed.Value(callOpts())
+				errCheck(err, quiet, "Failed to obtain bid value")
+				recoverable = new(big.Int).Div(new(big.Int).Mul(value, big.NewInt(5)), big.NewInt(1000))
+				withdraw = func(opts *bind.TransactOpts) (*types.Transaction, error) {
+					return ensClient.CancelBid(commandCtx, opts, args[0], bidder, bidPrice, auctionWithdrawSalt)
+				}
+			}
+		}
+		if withdraw == nil {
+			if entry.State == "Owned" {
+				cli.Err(quiet, "Nothing to recover; the deposit of a bid that won can be recovered by giving up the name with 'ens auction release'")
+			}
+			cli.Err(quiet, "Nothing to recover; revealed bids that lost were refunded when they were revealed")
+		}
+		if !quiet {
+			fmt.Println("Recoverable:", etherutils.WeiToString(recoverable, true))
+		}
+
+		// Fetch the wallet and account for the address
+		wallet, account, err := obtainWalletAndAccount(bidder, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the address")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := withdraw(opts)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address":     bidder.Hex(),
+			"recoverable": recoverable}, "Auction withdraw")
+	},
+}
+
+func init() {
+	auctionCmd.AddCommand(auctionWithdrawCmd)
+
+	auctionWithdrawCmd.Flags().StringVarP(&auctionWithdrawAddressStr, "address", "a", "", "Address that placed the bid")
+	auctionWithdrawCmd.Flags().StringVarP(&auctionWithdrawBidPriceStr, "bid", "b", "0.01 Ether", "Bid price used when bidding")
+	auctionWithdrawCmd.Flags().StringVarP(&auctionWithdrawSalt, "salt", "s", "", "Memorable phrase used when bidding, required to cancel an unrevealed bid")
+	addTransactionFlags(auctionWithdrawCmd, "Passphrase for the account that placed the bid")
+}
@@ -17,11 +17,16 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
 
+type availabilityResult struct {
+	Name     string `json:"name"`
+	NameHash string `json:"namehash"`
+	State    string `json:"state"`
+}
+
 // availabilityCmd represents the availability command
 var availabilityCmd = &cobra.Command{
 	Use:   "availability",
@@ -30,30 +35,32 @@ var availabilityCmd = &cobra.Command{
 
     ens availability enstest.eth
 
-In quiet mode this will return 0 if the domain is availabile, otherwise 1.`,
+In quiet mode this will return 0 if the domain is availabile, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		registrarContract, err := ens.RegistrarContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain registrar contract")
 		if ens.DomainLevel(args[0]) == 1 {
 			// Top-level domain
-			state, err := ens.State(registrarContract, client, args[0])
-			cli.ErrCheck(err, quiet, "Cannot obtain info")
+			state, err := ensClient.AuctionState(commandCtx, args[0])
+			errCheck(err, quiet, "Cannot obtain info")
 			if quiet {
 				if state == "Available" {
 					os.Exit(0)
 				} else {
 					os.Exit(1)
 				}
+			} else if jsonOutput {
+				outputJSON(&availabilityResult{
+					Name:     args[0],
+					NameHash: nameHashHex(args[0]),
+					State:    state,
+				})
 			} else {
 				fmt.Println(state)
 			}
 		} else {
 			// Subdomain
-			registry, err := ens.RegistryContract(client)
-			cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-			subdomainOwnerAddress, err := registry.Owner(nil, ens.NameHash(args[0]))
-			cli.ErrCheck(err, quiet, "Failed to obtain subdomain owner")
+			subdomainOwnerAddress, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+			errCheck(err, quiet, "Failed to obtain subdomain owner")
 			if quiet {
 				if subdomainOwnerAddress == ens.UnknownAddress {
 					os.Exit(0)
@@ -61,10 +68,18 @@ In quiet mode this will return 0 if the domain is availabile, otherwise 1.`,
 					os.Exit(1)
 				}
 			} else {
+				state := "Owned"
 				if subdomainOwnerAddress == ens.UnknownAddress {
-					fmt.Println("Available")
+					state = "Available"
+				}
+				if jsonOutput {
+					outputJSON(&availabilityResult{
+						Name:     args[0],
+						NameHash: nameHashHex(args[0]),
+						State:    state,
+					})
 				} else {
-					fmt.Println("Owned")
+					fmt.Println(state)
 				}
 			}
 		}
@@ -0,0 +1,111 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var batchStateFile string
+
+// Statuses of entries in a batch state file
+const (
+	batchEntrySent      = "sent"
+	batchEntryConfirmed = "confirmed"
+)
+
+// batchStateEntry is a record of an entry in a batch, stored as a line of
+// JSON in the batch state file.  Later lines for a name supersede earlier ones
+type batchStateEntry struct {
+	Name          string    `json:"name"`
+	Value         string    `json:"value"`
+	Status        string    `json:"status"`
+	TransactionID string    `json:"transactionid,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// readBatchState reads the latest entry for each name from the batch state
+// file, which need not exist
+func readBatchState() (map[string]*batchStateEntry, error) {
+	entries := make(map[string]*batchStateEntry)
+	if batchStateFile == "" {
+		return entries, nil
+	}
+	f, err := os.Open(batchStateFile)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := &batchStateEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, err
+		}
+		entries[entry.Name] = entry
+	}
+	return entries, scanner.Err()
+}
+
+// recordBatchState appends an entry to the batch state file
+func recordBatchState(name string, value string, status string, tx *types.Transaction) error {
+	if batchStateFile == "" || !sendsTransactions() {
+		return nil
+	}
+	entry := &batchStateEntry{
+		Name:      name,
+		Value:     value,
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+	if tx != nil {
+		entry.TransactionID = tx.Hash().Hex()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(batchStateFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// batchTransactionPending returns true if the transaction of an entry in the
+// batch state file is still waiting to be mined
+func batchTransactionPending(entry *batchStateEntry) bool {
+	if entry.TransactionID == "" {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	_, pending, err := client.TransactionByHash(ctx, common.HexToHash(entry.TransactionID))
+	return err == nil && pending
+}
@@ -0,0 +1,104 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+var bidJournal string
+
+// bidJournalEntry is a record of a bid, stored as a line of JSON in the bid journal
+type bidJournalEntry struct {
+	Name          string    `json:"name"`
+	Bidder        string    `json:"bidder"`
+	Value         string    `json:"value"`
+	Mask          string    `json:"mask"`
+	Salt          string    `json:"salt"`
+	Timestamp     time.Time `json:"timestamp"`
+	TransactionID string    `json:"transactionid,omitempty"`
+}
+
+// addBidJournalFlag adds the flag for the location of the bid journal
+func addBidJournalFlag(cmd *cobra.Command) {
+	defaultJournal := ""
+	if home, err := homedir.Dir(); err == nil {
+		defaultJournal = filepath.Join(home, ".ens-bids.jsonl")
+	}
+	cmd.Flags().StringVar(&bidJournal, "bid-journal", defaultJournal, "File in which bids are recorded so that they can be revealed later")
+}
+
+// recordBid appends a bid to the bid journal.  It is called before the bid
+// is sent, so that the salt is kept even if the command fails afterwards;
+// the transaction is signed by then so its ID is already known, unless it
+// is written with --output-tx to be signed elsewhere
+func recordBid(name string, bidder common.Address, value *big.Int, mask *big.Int, salt string, tx *types.Transaction) error {
+	if bidJournal == "" || dryRun || estimateOnly {
+		return nil
+	}
+	entry := &bidJournalEntry{
+		Name:      name,
+		Bidder:    bidder.Hex(),
+		Value:     value.String(),
+		Mask:      mask.String(),
+		Salt:      salt,
+		Timestamp: time.Now(),
+	}
+	if !signsElsewhere() {
+		entry.TransactionID = tx.Hash().Hex()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(bidJournal, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readBidJournal reads all bids from the bid journal
+func readBidJournal() ([]*bidJournalEntry, error) {
+	f, err := os.Open(bidJournal)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []*bidJournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := &bidJournalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,133 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+var bidListAll bool
+
+type bidListResult struct {
+	Name       string `json:"name"`
+	Bidder     string `json:"bidder"`
+	Value      string `json:"value"`
+	Salt       string `json:"salt"`
+	State      string `json:"state"`
+	Sealed     bool   `json:"sealed"`
+	RevealDate string `json:"revealdate,omitempty"`
+	RevealNow  bool   `json:"revealnow"`
+}
+
+// bidListCmd represents the bid list command
+var bidListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bids recorded in the bid journal",
+	Long: `List the bids recorded in the local bid journal, along with the state of their auctions.  For example:
+
+    ens bid list
+
+Bids placed with 'ens auction start' and 'ens auction bid' are recorded in the journal.  Bids that have not yet been revealed are shown, with those that are in their reveal window flagged; --all also shows bids that have been revealed or whose auctions are over.
+
+In quiet mode this will return 0 if there are bids that need to be revealed now, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := readBidJournal()
+		if os.IsNotExist(err) {
+			entries, err = nil, nil
+		}
+		errCheck(err, quiet, "Failed to read bid journal")
+
+		revealNow := false
+		for _, entry := range entries {
+			result, err := bidListEntry(entry)
+			if err != nil {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", entry.Name, err)
+				}
+				continue
+			}
+			revealNow = revealNow || result.RevealNow
+			if quiet || (!bidListAll && !result.Sealed) {
+				continue
+			}
+			if jsonOutput {
+				outputJSON(result)
+				continue
+			}
+			value, _ := new(big.Int).SetString(result.Value, 10)
+			status := result.State
+			switch {
+			case result.RevealNow:
+				status = "REVEAL NOW"
+			case result.Sealed && result.RevealDate != "":
+				status = "reveal from " + result.RevealDate
+			case !result.Sealed:
+				status += " (revealed)"
+			}
+			fmt.Printf("%s by %s for %s with salt %q: %s\n", result.Name, result.Bidder, etherutils.WeiToString(value, true), result.Salt, status)
+		}
+		if quiet {
+			if revealNow {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+	},
+}
+
+// bidListEntry obtains the current state of a bid from the journal
+func bidListEntry(entry *bidJournalEntry) (*bidListResult, error) {
+	value, ok := new(big.Int).SetString(entry.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %s", entry.Value)
+	}
+	bidder := common.HexToAddress(entry.Bidder)
+	auction, err := ensClient.AuctionEntry(commandCtx, entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	deed, err := ensClient.SealedBid(commandCtx, entry.Name, bidder, value, entry.Salt)
+	if err != nil {
+		return nil, err
+	}
+	result := &bidListResult{
+		Name:   entry.Name,
+		Bidder: entry.Bidder,
+		Value:  entry.Value,
+		Salt:   entry.Salt,
+		State:  auction.State,
+		Sealed: bytes.Compare(deed.Bytes(), ens.UnknownAddress.Bytes()) != 0,
+	}
+	if auction.State == "Bidding" || auction.State == "Revealing" {
+		result.RevealDate = auction.RegistrationDate.Add(-revealPeriod).UTC().Format(time.RFC1123)
+	}
+	result.RevealNow = result.Sealed && auction.State == "Revealing"
+	return result, nil
+}
+
+func init() {
+	bidCmd.AddCommand(bidListCmd)
+
+	bidListCmd.Flags().BoolVar(&bidListAll, "all", false, "Show all bids, including those that have been revealed")
+	addBidJournalFlag(bidListCmd)
+}
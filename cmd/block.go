@@ -0,0 +1,75 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+)
+
+var blockStr string
+
+// readBlock is the block at which state is read, or nil for the latest block
+var readBlock *big.Int
+
+// blockTags are the block tags accepted by --block
+var blockTags = map[string]rpc.BlockNumber{
+	"latest":    rpc.LatestBlockNumber,
+	"safe":      rpc.SafeBlockNumber,
+	"finalized": rpc.FinalizedBlockNumber,
+	"earliest":  rpc.EarliestBlockNumber,
+}
+
+// addBlockFlag adds the flag to read state at a historical block
+func addBlockFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&blockStr, "block", "", "Block at which to read state, as a number or one of latest, safe, finalized or earliest (default latest)")
+}
+
+// resolveBlock obtains the number of the block given by --block.  Tags are
+// resolved to a number so that all reads are made at the same block
+func resolveBlock(input string) (*big.Int, error) {
+	tag, isTag := blockTags[strings.ToLower(input)]
+	if !isTag {
+		number, ok := new(big.Int).SetString(input, 0)
+		if !ok || number.Sign() < 0 {
+			return nil, errors.New("block must be a number or one of latest, safe, finalized or earliest")
+		}
+		return number, nil
+	}
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, big.NewInt(int64(tag)))
+	if err != nil {
+		return nil, err
+	}
+	return header.Number, nil
+}
+
+// chainTime obtains the time of the latest block, which is the time used by
+// contracts and so is not affected by the skew of the local clock
+func chainTime() (time.Time, error) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Time), 0), nil
+}
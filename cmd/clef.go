@@ -0,0 +1,102 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+var clefEndpoint string
+
+// clefWallet is a wallet that signs through a clef instance.  Clef applies
+// its own policies and approval, so passphrases are ignored and requests that
+// clef does not approve are reported with its response
+type clefWallet struct {
+	*external.ExternalSigner
+}
+
+// SignTx asks clef to sign a transaction
+func (w *clefWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Waiting for clef to approve the transaction")
+	}
+	signed, err := w.ExternalSigner.SignTx(account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("clef did not sign the transaction: %v", err)
+	}
+	return signed, nil
+}
+
+// SignTxWithPassphrase asks clef to sign a transaction, ignoring the passphrase
+func (w *clefWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
+
+// SignText asks clef to sign a message
+func (w *clefWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Waiting for clef to approve the message")
+	}
+	signature, err := w.ExternalSigner.SignText(account, text)
+	if err != nil {
+		return nil, fmt.Errorf("clef did not sign the message: %v", err)
+	}
+	return signature, nil
+}
+
+// SignTextWithPassphrase asks clef to sign a message, ignoring the passphrase
+func (w *clefWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	return w.SignText(account, text)
+}
+
+// SignData asks clef to sign data of a given type
+func (w *clefWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Waiting for clef to approve the data")
+	}
+	signature, err := w.ExternalSigner.SignData(account, mimeType, data)
+	if err != nil {
+		return nil, fmt.Errorf("clef did not sign the data: %v", err)
+	}
+	return signature, nil
+}
+
+// SignDataWithPassphrase asks clef to sign data of a given type, ignoring the passphrase
+func (w *clefWallet) SignDataWithPassphrase(account accounts.Account, passphrase string, mimeType string, data []byte) ([]byte, error) {
+	return w.SignData(account, mimeType, data)
+}
+
+// obtainClefWalletAndAccount obtains a wallet that signs for an address
+// through the clef instance at the endpoint given by --clef
+func obtainClefWalletAndAccount(address common.Address) (accounts.Wallet, *accounts.Account, error) {
+	endpoint, err := homedir.Expand(clefEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to clef at %s: %v", clefEndpoint, err)
+	}
+	// Listing accounts needs its own approval in clef, so the account is not
+	// checked here; clef refuses to sign for accounts that it does not hold
+	return &clefWallet{signer}, &accounts.Account{Address: address}, nil
+}
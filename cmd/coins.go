@@ -0,0 +1,278 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ethCoinType is the SLIP-44 coin type for Ethereum
+const ethCoinType = 60
+
+// evmCoinTypeFlag is set on ENSIP-11 coin types for EVM-compatible chains
+const evmCoinTypeFlag = 0x80000000
+
+// coin holds the details required to encode and decode addresses for a
+// non-EVM coin
+type coin struct {
+	symbol       string
+	p2pkhVersion byte
+	p2shVersion  byte
+	bech32HRP    string
+}
+
+// coins are the non-EVM coins supported, keyed by SLIP-44 coin type
+var coins = map[uint64]*coin{
+	0: {symbol: "BTC", p2pkhVersion: 0x00, p2shVersion: 0x05, bech32HRP: "bc"},
+	2: {symbol: "LTC", p2pkhVersion: 0x30, p2shVersion: 0x32, bech32HRP: "ltc"},
+	3: {symbol: "DOGE", p2pkhVersion: 0x1e, p2shVersion: 0x16},
+}
+
+// parseCoinType parses a coin given as a SLIP-44 symbol or number
+func parseCoinType(input string) (uint64, error) {
+	if strings.ToUpper(input) == "ETH" {
+		return ethCoinType, nil
+	}
+	for coinType, coin := range coins {
+		if strings.ToUpper(input) == coin.symbol {
+			return coinType, nil
+		}
+	}
+	coinType, err := strconv.ParseUint(input, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unknown coin %s", input)
+	}
+	if _, exists := coins[coinType]; !exists && !isEVMCoinType(coinType) {
+		return 0, fmt.Errorf("unsupported coin type %d", coinType)
+	}
+	return coinType, nil
+}
+
+// isEVMCoinType returns true if a coin type uses Ethereum-style addresses
+func isEVMCoinType(coinType uint64) bool {
+	return coinType == ethCoinType || coinType&evmCoinTypeFlag != 0
+}
+
+// encodeCoinAddress encodes a textual address for a coin in to its binary form
+func encodeCoinAddress(coinType uint64, address string) ([]byte, error) {
+	if isEVMCoinType(coinType) {
+		if !common.IsHexAddress(address) {
+			return nil, errors.New("invalid address")
+		}
+		return common.HexToAddress(address).Bytes(), nil
+	}
+	coin := coins[coinType]
+	if coin.bech32HRP != "" && strings.HasPrefix(strings.ToLower(address), coin.bech32HRP+"1") {
+		return decodeSegwitAddress(coin.bech32HRP, address)
+	}
+	version, hash, err := base58CheckDecode(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 20 {
+		return nil, errors.New("invalid address length")
+	}
+	switch version {
+	case coin.p2pkhVersion:
+		script := append([]byte{0x76, 0xa9, 0x14}, hash...)
+		return append(script, 0x88, 0xac), nil
+	case coin.p2shVersion:
+		script := append([]byte{0xa9, 0x14}, hash...)
+		return append(script, 0x87), nil
+	default:
+		return nil, fmt.Errorf("address is not a %s address", coin.symbol)
+	}
+}
+
+// decodeCoinAddress decodes the binary form of an address for a coin in to its textual form
+func decodeCoinAddress(coinType uint64, data []byte) (string, error) {
+	if isEVMCoinType(coinType) {
+		if len(data) != common.AddressLength {
+			return "", errors.New("invalid address length")
+		}
+		return common.BytesToAddress(data).Hex(), nil
+	}
+	coin := coins[coinType]
+	switch {
+	case len(data) == 25 && bytes.HasPrefix(data, []byte{0x76, 0xa9, 0x14}) && bytes.HasSuffix(data, []byte{0x88, 0xac}):
+		return base58CheckEncode(coin.p2pkhVersion, data[3:23]), nil
+	case len(data) == 23 && bytes.HasPrefix(data, []byte{0xa9, 0x14}) && data[22] == 0x87:
+		return base58CheckEncode(coin.p2shVersion, data[2:22]), nil
+	case coin.bech32HRP != "" && len(data) >= 4 && (data[0] == 0 || (data[0] >= 0x51 && data[0] <= 0x60)) && int(data[1]) == len(data)-2:
+		return encodeSegwitAddress(coin.bech32HRP, data)
+	default:
+		return "", errors.New("unsupported address script")
+	}
+}
+
+func base58CheckEncode(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	return base58Encode(append(data, base58Checksum(data)...))
+}
+
+func base58CheckDecode(address string) (version byte, payload []byte, err error) {
+	data, err := base58Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, errors.New("address too short")
+	}
+	if !bytes.Equal(base58Checksum(data[:len(data)-4]), data[len(data)-4:]) {
+		return 0, nil, errors.New("invalid address checksum")
+	}
+	return data[0], data[1 : len(data)-4], nil
+}
+
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+// Bech32 and bech32m (BIP-173 and BIP-350) encoding of segwit addresses
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	res := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		res = append(res, byte(c>>5))
+	}
+	res = append(res, 0)
+	for _, c := range hrp {
+		res = append(res, byte(c&31))
+	}
+	return res
+}
+
+// convertBits regroups data from one bit width to another
+func convertBits(data []byte, from uint, to uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<to - 1
+	var res []byte
+	for _, value := range data {
+		if uint32(value)>>from != 0 {
+			return nil, errors.New("invalid data")
+		}
+		acc = acc<<from | uint32(value)
+		bits += from
+		for bits >= to {
+			bits -= to
+			res = append(res, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			res = append(res, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return res, nil
+}
+
+// decodeSegwitAddress decodes a bech32 segwit address in to its output script
+func decodeSegwitAddress(hrp string, address string) ([]byte, error) {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return nil, errors.New("mixed case address")
+	}
+	address = strings.ToLower(address)
+	separator := strings.LastIndex(address, "1")
+	if separator != len(hrp) || address[:separator] != hrp || len(address)-separator < 8 {
+		return nil, errors.New("invalid segwit address")
+	}
+	data := make([]byte, 0, len(address)-separator-1)
+	for _, c := range address[separator+1:] {
+		index := strings.IndexRune(bech32Charset, c)
+		if index == -1 {
+			return nil, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		data = append(data, byte(index))
+	}
+	witnessVersion := data[0]
+	expectedConst := uint32(bech32Const)
+	if witnessVersion != 0 {
+		expectedConst = bech32mConst
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != expectedConst {
+		return nil, errors.New("invalid address checksum")
+	}
+	program, err := convertBits(data[1:len(data)-6], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if witnessVersion > 16 || len(program) < 2 || len(program) > 40 || (witnessVersion == 0 && len(program) != 20 && len(program) != 32) {
+		return nil, errors.New("invalid witness program")
+	}
+	script := []byte{0, byte(len(program))}
+	if witnessVersion != 0 {
+		script[0] = 0x50 + witnessVersion
+	}
+	return append(script, program...), nil
+}
+
+// encodeSegwitAddress encodes a segwit output script as a bech32 address
+func encodeSegwitAddress(hrp string, script []byte) (string, error) {
+	witnessVersion := script[0]
+	checksumConst := uint32(bech32Const)
+	if witnessVersion != 0 {
+		witnessVersion -= 0x50
+		checksumConst = bech32mConst
+	}
+	program, err := convertBits(script[2:], 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data := append([]byte{witnessVersion}, program...)
+	polymod := bech32Polymod(append(append(bech32HRPExpand(hrp), data...), 0, 0, 0, 0, 0, 0)) ^ checksumConst
+	for i := 0; i < 6; i++ {
+		data = append(data, byte(polymod>>uint(5*(5-i))&31))
+	}
+	var res strings.Builder
+	res.WriteString(hrp)
+	res.WriteString("1")
+	for _, d := range data {
+		res.WriteByte(bech32Charset[d])
+	}
+	return res.String(), nil
+}
@@ -0,0 +1,101 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+var confirm bool
+var assumeYes bool
+
+// confirmTransaction prints a summary of a transaction, including the cost of
+// the gas it is expected to use, and asks the user to confirm that it should
+// be sent.  It returns true if the transaction should be sent, which is
+// always the case in quiet mode or with --yes
+func confirmTransaction(tx *types.Transaction, gas uint64, fields log.Fields, msg string) bool {
+	if !confirm || assumeYes || quiet {
+		return true
+	}
+	fmt.Println(msg)
+	if name, exists := fields["name"]; exists {
+		fmt.Println("Name:", name)
+	}
+	if tx.To() != nil {
+		fmt.Println("To:", tx.To().Hex())
+	}
+	fmt.Println("Value:", etherutils.WeiToString(tx.Value(), true))
+	// The fee cap of a legacy transaction is its gas price
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), tx.GasFeeCap())
+	if tx.Type() == types.DynamicFeeTxType {
+		fmt.Println("Maximum gas cost:", etherutils.WeiToString(fee, true))
+	} else {
+		fmt.Println("Gas cost:", etherutils.WeiToString(fee, true))
+	}
+	fmt.Print("Type 'yes' to send the transaction: ")
+	return readConfirmation()
+}
+
+// confirmBatch asks the user to confirm that a batch of transactions should
+// be sent.  Once confirmed the individual transactions are not confirmed
+func confirmBatch(msg string) bool {
+	if !confirm || assumeYes || quiet || estimateOnly {
+		return true
+	}
+	fmt.Println(msg)
+	fmt.Print("Type 'yes' to send the transactions: ")
+	if !readConfirmation() {
+		return false
+	}
+	assumeYes = true
+	return true
+}
+
+// confirmWarning prints a warning and asks the user to confirm that the
+// command should continue.  It returns true if the command should continue,
+// which is always the case in quiet mode or with --yes
+func confirmWarning(warning string) bool {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "WARNING:", warning)
+	}
+	if !confirm || assumeYes || quiet || estimateOnly {
+		return true
+	}
+	fmt.Print("Type 'yes' to continue: ")
+	return readConfirmation()
+}
+
+// readConfirmation returns true if the user types 'yes'
+func readConfirmation() bool {
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// confirmByDefault returns true if transactions should be confirmed when
+// --confirm is not supplied, which is the case when a terminal is attached
+func confirmByDefault() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
@@ -0,0 +1,142 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var contentGateway string
+
+// contentGatewayTimeout is the time allowed for a gateway to answer
+const contentGatewayTimeout = 30 * time.Second
+
+type contentResult struct {
+	Name          string `json:"name"`
+	NameHash      string `json:"namehash"`
+	Content       string `json:"content"`
+	ContentHash   string `json:"contenthash"`
+	GatewayURL    string `json:"gatewayurl,omitempty"`
+	GatewayStatus string `json:"gatewaystatus,omitempty"`
+	ContentLength int64  `json:"contentlength,omitempty"`
+}
+
+// contentCmd represents the content command
+var contentCmd = &cobra.Command{
+	Use:   "content",
+	Short: "Obtain the content hash associated with an ENS name",
+	Long: `Obtain the content hash (EIP-1577) associated with a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens content enstest.eth
+
+The protocol of the content (IPFS, IPNS, Swarm or Arweave) is detected from the content hash and shown as the prefix of the content.
+
+IPFS and IPNS content can be checked through a gateway with --gateway, which requests the root of the content from the gateway and shows the HTTP status and length of the content without downloading it.  For example:
+
+    ens content --gateway=https://ipfs.io enstest.eth
+
+In quiet mode this will return 0 if the name has a content hash and, if a gateway is given, the gateway serves the content, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hash, err := ensClient.Contenthash(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain content hash")
+		assert(len(hash) > 0, exitNotFound, "No content hash for that name")
+		content, err := decodeContenthash(hash)
+		errCheck(err, quiet, "Failed to decode content hash")
+		result := &contentResult{
+			Name:        args[0],
+			NameHash:    nameHashHex(args[0]),
+			Content:     content,
+			ContentHash: hexutil.Encode(hash),
+		}
+		var resp *http.Response
+		if contentGateway != "" {
+			result.GatewayURL, err = contentGatewayURL(contentGateway, content)
+			errCheck(err, quiet, "Cannot check that content through a gateway")
+			resp, err = fetchContentRoot(result.GatewayURL)
+			errCheck(err, quiet, "Failed to obtain content from the gateway")
+			result.GatewayStatus = resp.Status
+			if resp.ContentLength >= 0 {
+				result.ContentLength = resp.ContentLength
+			}
+		}
+		if !quiet {
+			if jsonOutput {
+				outputJSON(result)
+			} else {
+				fmt.Println(content)
+				if resp != nil {
+					fmt.Printf("%-18s%s\n", "Gateway URL:", result.GatewayURL)
+					fmt.Printf("%-18s%s\n", "Gateway status:", result.GatewayStatus)
+					if resp.ContentLength >= 0 {
+						fmt.Printf("%-18s%d bytes\n", "Content length:", result.ContentLength)
+					} else {
+						fmt.Printf("%-18s%s\n", "Content length:", "unknown")
+					}
+				}
+			}
+		}
+		assert(resp == nil || resp.StatusCode < 300, exitNotFound, "Gateway does not serve the content")
+	},
+}
+
+// contentGatewayURL returns the URL of the root of IPFS or IPNS content on a gateway
+func contentGatewayURL(gateway string, content string) (string, error) {
+	parts := strings.SplitN(content, "://", 2)
+	if len(parts) != 2 || (parts[0] != "ipfs" && parts[0] != "ipns") {
+		return "", fmt.Errorf("only IPFS and IPNS content can be checked, not %s", content)
+	}
+	base, err := url.Parse(gateway)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return "", fmt.Errorf("invalid gateway %s", gateway)
+	}
+	return fmt.Sprintf("%s/%s/%s/", strings.TrimSuffix(base.String(), "/"), parts[0], parts[1]), nil
+}
+
+// fetchContentRoot requests the root of content from a gateway without
+// reading the content.  HEAD is used where the gateway supports it
+func fetchContentRoot(contentURL string) (*http.Response, error) {
+	httpClient := &http.Client{Timeout: contentGatewayTimeout}
+	resp, err := contentRequest(httpClient, http.MethodHead, contentURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = contentRequest(httpClient, http.MethodGet, contentURL)
+	}
+	return resp, err
+}
+
+// contentRequest sends a request to a gateway, closing the body of the response
+func contentRequest(httpClient *http.Client, method string, contentURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(commandCtx, method, contentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+func init() {
+	RootCmd.AddCommand(contentCmd)
+
+	contentCmd.Flags().StringVar(&contentGateway, "gateway", "", "URL of an IPFS gateway through which to check that the content is served")
+}
@@ -0,0 +1,60 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+)
+
+const testCID = "QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4"
+
+func TestContentGatewayURL(t *testing.T) {
+	urls := map[string]string{
+		"https://ipfs.io":       "https://ipfs.io/ipfs/" + testCID + "/",
+		"https://ipfs.io/":      "https://ipfs.io/ipfs/" + testCID + "/",
+		"http://localhost:8080": "http://localhost:8080/ipfs/" + testCID + "/",
+	}
+	for gateway, expected := range urls {
+		url, err := contentGatewayURL(gateway, "ipfs://"+testCID)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", gateway, err)
+		} else if url != expected {
+			t.Errorf("%s: URL is %s, expected %s", gateway, url, expected)
+		}
+	}
+
+	url, err := contentGatewayURL("https://ipfs.io", "ipns://enstest.eth")
+	if err != nil || url != "https://ipfs.io/ipns/enstest.eth/" {
+		t.Errorf("IPNS URL is %s (%v), expected https://ipfs.io/ipns/enstest.eth/", url, err)
+	}
+}
+
+func TestContentGatewayURLUnsupported(t *testing.T) {
+	// Only IPFS and IPNS content can be fetched from a gateway
+	for _, content := range []string{
+		"bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162",
+		"ar://pZXEhksCp2FNDZ5zHErEmjCUxCEOjRlxwJ8fHmoJWtw",
+		testCID,
+	} {
+		if _, err := contentGatewayURL("https://ipfs.io", content); err == nil {
+			t.Errorf("%s: expected error", content)
+		}
+	}
+	// Gateways must be HTTP or HTTPS URLs
+	for _, gateway := range []string{"ftp://ipfs.io", "ipfs.io", ""} {
+		if _, err := contentGatewayURL(gateway, "ipfs://"+testCID); err == nil {
+			t.Errorf("%q: expected error", gateway)
+		}
+	}
+}
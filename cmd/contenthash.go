@@ -0,0 +1,283 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Multicodec values used in EIP-1577 content hashes
+const (
+	ipfsNsCodec        = 0xe3
+	swarmNsCodec       = 0xe4
+	ipnsNsCodec        = 0xe5
+	arweaveNsCodec     = 0xb29910
+	cidV1              = 0x01
+	dagPbCodec         = 0x70
+	libp2pKeyCodec     = 0x72
+	swarmManifestCodec = 0xfa
+	identityCode       = 0x00
+	sha256Code         = 0x12
+	keccak256Code      = 0x1b
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+const base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// contentProtocols are the URI schemes of the protocols supported in content hashes, keyed by protocol name
+var contentProtocols = map[string]string{
+	"ipfs":    "ipfs://",
+	"ipns":    "ipns://",
+	"swarm":   "bzz://",
+	"bzz":     "bzz://",
+	"arweave": "ar://",
+	"ar":      "ar://",
+}
+
+var base32Encoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// encodeContenthash encodes content of the form ipfs://<cid>, ipns://<cid>, bzz://<hash> or ar://<id> as an
+// EIP-1577 content hash.  Raw 0x-prefixed content hashes are accepted if they have a known codec.
+func encodeContenthash(content string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(content, "0x"):
+		data, err := hex.DecodeString(content[2:])
+		if err != nil {
+			return nil, err
+		}
+		if _, err = decodeContenthash(data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	case strings.HasPrefix(content, "ipfs://"):
+		cid, err := decodeIpfsCid(content[7:])
+		if err != nil {
+			return nil, err
+		}
+		return append(uvarint(ipfsNsCodec), cid...), nil
+	case strings.HasPrefix(content, "ipns://"):
+		cid, err := decodeIpnsCid(content[7:])
+		if err != nil {
+			return nil, err
+		}
+		return append(uvarint(ipnsNsCodec), cid...), nil
+	case strings.HasPrefix(content, "bzz://"):
+		hash, err := hex.DecodeString(content[6:])
+		if err != nil {
+			return nil, err
+		}
+		if len(hash) != 32 {
+			return nil, errors.New("swarm hash must be 32 bytes")
+		}
+		data := uvarint(swarmNsCodec)
+		data = append(data, uvarint(cidV1)...)
+		data = append(data, uvarint(swarmManifestCodec)...)
+		data = append(data, keccak256Code, 32)
+		return append(data, hash...), nil
+	case strings.HasPrefix(content, "ar://"):
+		id, err := base64.RawURLEncoding.DecodeString(content[5:])
+		if err != nil {
+			return nil, err
+		}
+		if len(id) != 32 {
+			return nil, errors.New("arweave transaction ID must be 32 bytes")
+		}
+		return append(uvarint(arweaveNsCodec), id...), nil
+	default:
+		return nil, errors.New("content must start with ipfs://, ipns://, bzz:// or ar://")
+	}
+}
+
+// contentWithProtocol adds the URI scheme of a protocol to content that does
+// not have one, ensuring that content that does matches the protocol
+func contentWithProtocol(content string, protocol string) (string, error) {
+	prefix, exists := contentProtocols[strings.ToLower(protocol)]
+	if !exists {
+		return "", fmt.Errorf("unknown protocol %s; supported protocols are ipfs, ipns, swarm and arweave", protocol)
+	}
+	if !strings.Contains(content, "://") {
+		return prefix + content, nil
+	}
+	if !strings.HasPrefix(content, prefix) {
+		return "", fmt.Errorf("content %s is not for protocol %s", content, protocol)
+	}
+	return content, nil
+}
+
+// decodeContenthash decodes an EIP-1577 content hash in to a URI
+func decodeContenthash(data []byte) (string, error) {
+	codec, n := binary.Uvarint(data)
+	if n <= 0 {
+		return "", errors.New("invalid content hash codec")
+	}
+	data = data[n:]
+	switch codec {
+	case ipfsNsCodec:
+		cid, err := encodeIpfsCid(data)
+		if err != nil {
+			return "", err
+		}
+		return "ipfs://" + cid, nil
+	case ipnsNsCodec:
+		if len(data) < 2 || data[0] != cidV1 || data[1] != libp2pKeyCodec {
+			return "", errors.New("invalid IPNS content hash")
+		}
+		return "ipns://k" + baseEncode(data, base36Alphabet), nil
+	case swarmNsCodec:
+		prefix := append(uvarint(cidV1), uvarint(swarmManifestCodec)...)
+		prefix = append(prefix, keccak256Code, 32)
+		if len(data) != len(prefix)+32 || !bytes.HasPrefix(data, prefix) {
+			return "", errors.New("invalid swarm content hash")
+		}
+		return "bzz://" + hex.EncodeToString(data[len(prefix):]), nil
+	case arweaveNsCodec:
+		if len(data) != 32 {
+			return "", errors.New("invalid arweave content hash")
+		}
+		return "ar://" + base64.RawURLEncoding.EncodeToString(data), nil
+	default:
+		return "", fmt.Errorf("unsupported content hash codec 0x%x", codec)
+	}
+}
+
+// decodeIpnsCid decodes an IPNS name, which is a libp2p key as a base36 (k...)
+// or base32 (b...) CIDv1 or a base58 peer ID, in to binary CIDv1
+func decodeIpnsCid(cid string) ([]byte, error) {
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(cid, "k"):
+		data, err = baseDecode(cid[1:], base36Alphabet)
+	case strings.HasPrefix(cid, "b"):
+		data, err = base32Encoding.DecodeString(cid[1:])
+	case strings.HasPrefix(cid, "Qm") || strings.HasPrefix(cid, "1"):
+		// Peer ID, which is a raw multihash
+		var multihash []byte
+		multihash, err = base58Decode(cid)
+		if err == nil && (len(multihash) < 2 || (multihash[0] != identityCode && multihash[0] != sha256Code)) {
+			err = errors.New("invalid peer ID")
+		}
+		data = append([]byte{cidV1, libp2pKeyCodec}, multihash...)
+	default:
+		return nil, errors.New("IPNS name must be a base36 or base32 CIDv1 or a base58 peer ID")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2 || data[0] != cidV1 || data[1] != libp2pKeyCodec {
+		return nil, errors.New("IPNS name must be a libp2p key")
+	}
+	return data, nil
+}
+
+// decodeIpfsCid decodes a CIDv0 (Qm...) or base32 CIDv1 (b...) string in to binary CIDv1
+func decodeIpfsCid(cid string) ([]byte, error) {
+	if strings.HasPrefix(cid, "Qm") {
+		multihash, err := base58Decode(cid)
+		if err != nil {
+			return nil, err
+		}
+		if len(multihash) != 34 || multihash[0] != sha256Code || multihash[1] != 32 {
+			return nil, errors.New("invalid CIDv0")
+		}
+		return append([]byte{cidV1, dagPbCodec}, multihash...), nil
+	}
+	if strings.HasPrefix(cid, "b") {
+		data, err := base32Encoding.DecodeString(cid[1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 || data[0] != cidV1 {
+			return nil, errors.New("invalid CIDv1")
+		}
+		return data, nil
+	}
+	return nil, errors.New("CID must be base58 CIDv0 or base32 CIDv1")
+}
+
+// encodeIpfsCid encodes a binary CIDv1 as a string, using CIDv0 where possible
+func encodeIpfsCid(cid []byte) (string, error) {
+	if len(cid) < 2 || cid[0] != cidV1 {
+		return "", errors.New("invalid CIDv1")
+	}
+	if cid[1] == dagPbCodec && len(cid) == 36 && cid[2] == sha256Code && cid[3] == 32 {
+		return base58Encode(cid[2:]), nil
+	}
+	return "b" + base32Encoding.EncodeToString(cid), nil
+}
+
+// uvarint encodes a value as an unsigned varint
+func uvarint(value uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, value)
+	return buf[:n]
+}
+
+func base58Encode(data []byte) string {
+	return baseEncode(data, base58Alphabet)
+}
+
+func base58Decode(input string) ([]byte, error) {
+	return baseDecode(input, base58Alphabet)
+}
+
+// baseEncode encodes data with an alphabet, representing leading zero bytes
+// with the first character of the alphabet as base58 does
+func baseEncode(data []byte, alphabet string) string {
+	value := new(big.Int).SetBytes(data)
+	base := big.NewInt(int64(len(alphabet)))
+	mod := new(big.Int)
+	var res []byte
+	for value.Sign() > 0 {
+		value.DivMod(value, base, mod)
+		res = append(res, alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		res = append(res, alphabet[0])
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return string(res)
+}
+
+// baseDecode decodes data encoded with baseEncode
+func baseDecode(input string, alphabet string) ([]byte, error) {
+	value := big.NewInt(0)
+	base := big.NewInt(int64(len(alphabet)))
+	for _, c := range input {
+		index := strings.IndexRune(alphabet, c)
+		if index == -1 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		value.Mul(value, base)
+		value.Add(value, big.NewInt(int64(index)))
+	}
+	var leadingZeros int
+	for leadingZeros < len(input) && input[leadingZeros] == alphabet[0] {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), value.Bytes()...), nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var contentSetContent string
+var contentSetProtocol string
+
+// contentSetCmd represents the content set command
+var contentSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the content hash of an ENS name",
+	Long: `Set the content hash (EIP-1577) of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens content set --content=ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4 --passphrase="my secret passphrase" enstest.eth
+
+Content can be an IPFS CID (ipfs://...), an IPNS name (ipns://...), a Swarm hash (bzz://...), an Arweave transaction ID (ar://...) or a raw 0x-prefixed content hash.  If --protocol is supplied (ipfs, ipns, swarm or arweave) the content can be given without its prefix, for example:
+
+    ens content set --protocol=ipns --content=k51qzi5uqu5dihst24f3rp2ej4co9berxohfkxaenbq1wjty7nrd5e9xp4afx1 --passphrase="my secret passphrase" enstest.eth
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the content hash is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(contentSetContent != "", exitBadInput, "Content is required")
+		if contentSetProtocol != "" {
+			var err error
+			contentSetContent, err = contentWithProtocol(contentSetContent, contentSetProtocol)
+			errCheck(badInput(err), quiet, "Invalid content")
+		}
+		hash, err := encodeContenthash(contentSetContent)
+		errCheck(badInput(err), quiet, "Invalid content")
+
+		// Ensure that the name is in a suitable state
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set content")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		// Obtain the resolver for this name
+		resolverAddress, err := ens.Resolver(registryContract, args[0])
+		errCheck(err, quiet, "No resolver for that name")
+		err = checkResolverInterface(resolverAddress, "contenthash")
+		errCheck(err, quiet, "Resolver cannot hold content hashes")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		tx, err := ensClient.SetContenthash(commandCtx, opts, args[0], hash)
+		errCheck(err, quiet, "Failed to set content hash for that name")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"content": contentSetContent}, "Content set")
+	},
+}
+
+func init() {
+	contentCmd.AddCommand(contentSetCmd)
+
+	contentSetCmd.Flags().StringVarP(&contentSetContent, "content", "t", "", "Content to associate with the name")
+	contentSetCmd.Flags().StringVar(&contentSetProtocol, "protocol", "", "Protocol of the content (ipfs, ipns, swarm or arweave), if the content has no prefix")
+	addTransactionFlags(contentSetCmd, "Passphrase for the account that owns the name")
+}
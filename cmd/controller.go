@@ -0,0 +1,131 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	ensclient "github.com/orinocopay/ens/client"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+)
+
+// minNameLength is the minimum number of characters in a label registered with the controller
+const minNameLength = 3
+
+// shortNameLength is the number of characters below which the price oracle
+// charges a higher rent for a label
+const shortNameLength = 5
+
+// minRegistrationDuration is the shortest period for which a name can be registered
+var minRegistrationDuration = 28 * 24 * time.Hour
+
+// gracePeriod is the period after expiry during which a name can still be renewed by its registrant
+var gracePeriod = 90 * 24 * time.Hour
+
+var fiatCurrency string
+var paymentValueStr string
+
+// printPrice prints a price, along with its value in the currency given by --fiat if supplied
+func printPrice(price *big.Int) {
+	if quiet {
+		return
+	}
+	fmt.Println("Price is", etherutils.WeiToString(price, true))
+	if fiatCurrency == "" {
+		return
+	}
+	assert(strings.ToUpper(fiatCurrency) == "USD", exitBadInput, "The price oracle only supports USD")
+	usdPrice, err := ensClient.ETHUSDPrice(commandCtx)
+	errCheck(err, quiet, "Failed to obtain USD price of Ether")
+	// price is in Wei and usdPrice has USDPriceDecimals decimals
+	value := new(big.Float).SetInt(new(big.Int).Mul(price, usdPrice))
+	value.Quo(value, new(big.Float).SetFloat64(math.Pow10(18+ensclient.USDPriceDecimals)))
+	fmt.Printf("Price is approximately %s USD\n", value.Text('f', 2))
+}
+
+// shortNameWarning returns a warning giving the yearly price of a label that
+// is short enough to be charged a higher rent, or an empty string if it is not
+func shortNameWarning(label string) (string, error) {
+	length := utf8.RuneCountInString(label)
+	if length >= shortNameLength {
+		return "", nil
+	}
+	base, premium, err := ensClient.RentPrice(commandCtx, label, 365*24*time.Hour)
+	if err != nil {
+		return "", err
+	}
+	warning := fmt.Sprintf("%s has %d characters; names with fewer than %d characters cost more, and this one costs %s a year", label, length, shortNameLength, etherutils.WeiToString(base, true))
+	if premium.Sign() > 0 {
+		warning += fmt.Sprintf(" plus a temporary premium of %s", etherutils.WeiToString(premium, true))
+	}
+	return warning, nil
+}
+
+// paymentValue returns the value to send to pay a price.  This is the value
+// given by --value, which must cover the price, or otherwise the price plus
+// 10% to allow for price fluctuations; any excess is refunded
+func paymentValue(price *big.Int) (*big.Int, error) {
+	if paymentValueStr == "" {
+		return new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(110)), big.NewInt(100)), nil
+	}
+	value, err := etherutils.StringToWei(paymentValueStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %s", paymentValueStr)
+	}
+	if value.Cmp(price) < 0 {
+		return nil, fmt.Errorf("value %s is less than the price %s", etherutils.WeiToString(value, true), etherutils.WeiToString(price, true))
+	}
+	return value, nil
+}
+
+// addValueFlag adds the flag for the value to send in place of the price plus 10%
+func addValueFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&paymentValueStr, "value", "", "Value to send, which must cover the price (defaults to the price plus 10%, with any excess refunded)")
+}
+
+// addFiatFlag adds the flag for the fiat currency in which to show prices
+func addFiatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&fiatCurrency, "fiat", "", "Also show prices in this fiat currency (only USD is supported)")
+}
+
+// parseRegistrationDuration parses a registration duration.  As well as
+// standard durations this accepts a number of days (e.g. 90d) or years
+// (e.g. 2y, where a year is 365 days)
+func parseRegistrationDuration(input string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(input, "y"):
+		unit = 365 * 24 * time.Hour
+	case strings.HasSuffix(input, "d"):
+		unit = 24 * time.Hour
+	default:
+		return time.ParseDuration(input)
+	}
+	count, err := strconv.Atoi(input[:len(input)-1])
+	if err != nil {
+		return 0, err
+	}
+	if count <= 0 {
+		return 0, errors.New("duration must be positive")
+	}
+	return time.Duration(count) * unit, nil
+}
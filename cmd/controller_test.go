@@ -0,0 +1,58 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPaymentValue(t *testing.T) {
+	ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	tests := []struct {
+		name     string
+		price    *big.Int
+		valueStr string
+		value    *big.Int
+		err      bool
+	}{
+		{name: "Default", price: ether, value: big.NewInt(1100000000000000000)},
+		{name: "DefaultRoundsDown", price: big.NewInt(15), value: big.NewInt(16)},
+		{name: "DefaultZero", price: big.NewInt(0), value: big.NewInt(0)},
+		{name: "Value", price: ether, valueStr: "2 Ether", value: new(big.Int).Mul(ether, big.NewInt(2))},
+		{name: "ValueEqualsPrice", price: ether, valueStr: "1 Ether", value: ether},
+		{name: "ValueBelowPrice", price: ether, valueStr: "0.5 Ether", err: true},
+		{name: "InvalidValue", price: ether, valueStr: "lots", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			paymentValueStr = test.valueStr
+			defer func() { paymentValueStr = "" }()
+			value, err := paymentValue(test.price)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error, obtained %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value.Cmp(test.value) != 0 {
+				t.Errorf("value is %v, expected %v", value, test.value)
+			}
+		})
+	}
+}
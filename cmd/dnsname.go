@@ -0,0 +1,83 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/spf13/cobra"
+)
+
+var dnsNameDecode bool
+
+type dnsNameResult struct {
+	Name    string `json:"name"`
+	DNSName string `json:"dnsname"`
+}
+
+// dnsNameCmd represents the dnsname command
+var dnsNameCmd = &cobra.Command{
+	Use:   "dnsname",
+	Short: "Obtain the DNS wire format of a name",
+	Long: `Obtain the DNS wire format of a name, as used by wildcard resolution (ENSIP-10) and DNS names in ENS.  For example:
+
+    ens dnsname enstest.eth
+
+The name is normalized but, as it might be a DNS name, '.eth' is not added.  With --decode a name in DNS wire format is decoded instead.  For example:
+
+    ens dnsname --decode 0x07656e73746573740365746800
+
+In quiet mode this will return 0 if the name can be encoded or decoded, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 1 && args[0] != "", exitBadInput, "This command requires a name")
+		var name string
+		var dnsName []byte
+		var err error
+		if dnsNameDecode {
+			dnsName, err = hexutil.Decode(args[0])
+			errCheck(badInput(err), quiet, "Invalid hex")
+			name, err = ensclient.DNSDecode(dnsName)
+			errCheck(badInput(err), quiet, "Invalid DNS name")
+		} else {
+			name, err = cleanName(args[0])
+			errCheck(badInput(err), quiet, "Invalid name")
+			name, err = normalizeName(name)
+			errCheck(badInput(err), quiet, "Invalid name")
+			dnsName, err = ensclient.DNSEncode(name)
+			errCheck(err, quiet, "Failed to encode name")
+		}
+		if quiet {
+			os.Exit(0)
+		}
+		if jsonOutput {
+			outputJSON(&dnsNameResult{
+				Name:    name,
+				DNSName: hexutil.Encode(dnsName),
+			})
+		} else if dnsNameDecode {
+			fmt.Println(name)
+		} else {
+			fmt.Println(hexutil.Encode(dnsName))
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(dnsNameCmd)
+
+	dnsNameCmd.Flags().BoolVar(&dnsNameDecode, "decode", false, "Decode a hex name in DNS wire format rather than encoding a name")
+}
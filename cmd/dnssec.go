@@ -0,0 +1,248 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/miekg/dns"
+	ensclient "github.com/orinocopay/ens/client"
+)
+
+var dnsServer string
+
+// maxDNSSECDepth is the maximum number of zones in a DNSSEC chain
+const maxDNSSECDepth = 16
+
+// dnssecAlgorithms are the DNSSEC signature algorithms supported by the ENS
+// DNSSEC oracle, in order of preference
+var dnssecAlgorithms = []uint8{dns.ECDSAP256SHA256, dns.RSASHA256, dns.RSASHA1NSEC3SHA1, dns.RSASHA1}
+
+// rootKeyTags are the tags of the root zone key signing keys that the ENS
+// DNSSEC oracle holds as trust anchors
+var rootKeyTags = []uint16{20326, 38696}
+
+// dnsQuery queries the DNS server for the records of a type for a name,
+// requesting DNSSEC signatures
+func dnsQuery(name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(4096, true)
+	dnsClient := &dns.Client{Timeout: 10 * time.Second}
+	resp, _, err := dnsClient.ExchangeContext(commandCtx, msg, dnsServer)
+	if err == nil && resp.Truncated {
+		dnsClient.Net = "tcp"
+		resp, _, err = dnsClient.ExchangeContext(commandCtx, msg, dnsServer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%s query for %s failed: %s", dns.TypeToString[qtype], name, dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// signedRRSet obtains the records of a type for a name along with their
+// signature, preferring the algorithms supported by the oracle.  If keyTags
+// is supplied only signatures by keys with those tags are considered, as
+// the oracle only accepts a set of keys signed by a key it can already prove
+func signedRRSet(name string, qtype uint16, keyTags []uint16) ([]dns.RR, *dns.RRSIG, error) {
+	resp, err := dnsQuery(name, qtype)
+	if err != nil {
+		return nil, nil, err
+	}
+	rrs := make([]dns.RR, 0)
+	sigs := make(map[uint8]*dns.RRSIG)
+	for _, rr := range resp.Answer {
+		if !strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			continue
+		}
+		if sig, isSig := rr.(*dns.RRSIG); isSig {
+			if sig.TypeCovered == qtype && (keyTags == nil || containsKeyTag(keyTags, sig.KeyTag)) {
+				sigs[sig.Algorithm] = sig
+			}
+		} else if rr.Header().Rrtype == qtype {
+			rrs = append(rrs, rr)
+		}
+	}
+	if len(rrs) == 0 {
+		return nil, nil, fmt.Errorf("no %s records for %s", dns.TypeToString[qtype], name)
+	}
+	if len(sigs) == 0 {
+		if keyTags != nil {
+			return nil, nil, fmt.Errorf("%s records for %s are not signed by a key in the parent zone's DS records", dns.TypeToString[qtype], name)
+		}
+		return nil, nil, fmt.Errorf("%s records for %s are not signed; DNSSEC must be enabled for the name", dns.TypeToString[qtype], name)
+	}
+	for _, algorithm := range dnssecAlgorithms {
+		if sig, exists := sigs[algorithm]; exists {
+			return rrs, sig, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s records for %s are not signed with an algorithm supported by ENS", dns.TypeToString[qtype], name)
+}
+
+// dsKeyTags returns the tags of the keys referred to by a set of DS records
+func dsKeyTags(rrs []dns.RR) []uint16 {
+	tags := make([]uint16, 0, len(rrs))
+	for _, rr := range rrs {
+		if ds, isDS := rr.(*dns.DS); isDS {
+			tags = append(tags, ds.KeyTag)
+		}
+	}
+	return tags
+}
+
+// containsKeyTag returns true if a key tag is one of a set of key tags
+func containsKeyTag(keyTags []uint16, keyTag uint16) bool {
+	for _, tag := range keyTags {
+		if tag == keyTag {
+			return true
+		}
+	}
+	return false
+}
+
+// dnssecProof obtains the chain of signed records that proves the records
+// of a type for a name, starting with the keys of the root zone.  It also
+// returns the proven records
+func dnssecProof(name string, qtype uint16) ([]ensclient.RRSetWithSignature, []dns.RR, error) {
+	rrs, sig, err := signedRRSet(name, qtype, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry, err := oracleRRSet(rrs, sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof := []ensclient.RRSetWithSignature{entry}
+
+	// Work up to the root, proving the keys of each zone with the DS
+	// records of its parent.  The keys must be signed by a key that the DS
+	// records refer to, or by a root trust anchor for the root zone
+	zone := sig.SignerName
+	for depth := 0; ; depth++ {
+		if depth == maxDNSSECDepth {
+			return nil, nil, errors.New("DNSSEC chain is too long")
+		}
+		keyTags := rootKeyTags
+		var ds []dns.RR
+		var dsSig *dns.RRSIG
+		if zone != "." {
+			if ds, dsSig, err = signedRRSet(zone, dns.TypeDS, nil); err != nil {
+				return nil, nil, err
+			}
+			keyTags = dsKeyTags(ds)
+		}
+		keys, keysSig, err := signedRRSet(zone, dns.TypeDNSKEY, keyTags)
+		if err != nil {
+			return nil, nil, err
+		}
+		if entry, err = oracleRRSet(keys, keysSig); err != nil {
+			return nil, nil, err
+		}
+		proof = append(proof, entry)
+		if zone == "." {
+			break
+		}
+		if entry, err = oracleRRSet(ds, dsSig); err != nil {
+			return nil, nil, err
+		}
+		proof = append(proof, entry)
+		zone = dsSig.SignerName
+	}
+
+	// The oracle requires the proof from the root down
+	for i, j := 0, len(proof)-1; i < j; i, j = i+1, j-1 {
+		proof[i], proof[j] = proof[j], proof[i]
+	}
+	return proof, rrs, nil
+}
+
+// oracleRRSet creates the signed set of records submitted to the oracle
+func oracleRRSet(rrs []dns.RR, sig *dns.RRSIG) (ensclient.RRSetWithSignature, error) {
+	rrset := make([]byte, 18)
+	binary.BigEndian.PutUint16(rrset[0:], sig.TypeCovered)
+	rrset[2] = sig.Algorithm
+	rrset[3] = sig.Labels
+	binary.BigEndian.PutUint32(rrset[4:], sig.OrigTtl)
+	binary.BigEndian.PutUint32(rrset[8:], sig.Expiration)
+	binary.BigEndian.PutUint32(rrset[12:], sig.Inception)
+	binary.BigEndian.PutUint16(rrset[16:], sig.KeyTag)
+	signer := make([]byte, 256)
+	offset, err := dns.PackDomainName(strings.ToLower(sig.SignerName), signer, 0, nil, false)
+	if err != nil {
+		return ensclient.RRSetWithSignature{}, err
+	}
+	rrset = append(rrset, signer[:offset]...)
+
+	// Records are in canonical form, with lower-case names and the original
+	// TTL, and ordered by their data
+	records := make([][]byte, 0, len(rrs))
+	dataOffset := 0
+	for _, rr := range rrs {
+		rr = dns.Copy(rr)
+		rr.Header().Name = strings.ToLower(rr.Header().Name)
+		rr.Header().Ttl = sig.OrigTtl
+		record := make([]byte, dns.Len(rr)+1)
+		offset, err := dns.PackRR(rr, record, 0, nil, false)
+		if err != nil {
+			return ensclient.RRSetWithSignature{}, err
+		}
+		records = append(records, record[:offset])
+		// Owner name, type, class, TTL and data length
+		dataOffset = len(rr.Header().Name) + 1 + 10
+		if rr.Header().Name == "." {
+			dataOffset = 1 + 10
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return bytes.Compare(records[i][dataOffset:], records[j][dataOffset:]) < 0
+	})
+	for _, record := range records {
+		rrset = append(rrset, record...)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return ensclient.RRSetWithSignature{}, err
+	}
+	return ensclient.RRSetWithSignature{Rrset: rrset, Sig: signature}, nil
+}
+
+// dnsClaimedOwner obtains the owner claimed for a DNS name from the 'a='
+// entry of its _ens TXT records
+func dnsClaimedOwner(rrs []dns.RR) (common.Address, error) {
+	for _, rr := range rrs {
+		txt, isTxt := rr.(*dns.TXT)
+		if !isTxt {
+			continue
+		}
+		for _, entry := range txt.Txt {
+			if strings.HasPrefix(entry, "a=") && common.IsHexAddress(entry[2:]) {
+				return common.HexToAddress(entry[2:]), nil
+			}
+		}
+	}
+	return common.Address{}, errors.New("no 'a=<address>' TXT record")
+}
@@ -0,0 +1,82 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/miekg/dns"
+)
+
+func TestOracleRRSet(t *testing.T) {
+	sig := &dns.RRSIG{
+		Hdr:         dns.RR_Header{Name: "Example.COM.", Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 300},
+		TypeCovered: dns.TypeA,
+		Algorithm:   dns.RSASHA256,
+		Labels:      2,
+		OrigTtl:     3600,
+		Expiration:  0x5e000000,
+		Inception:   0x5d000000,
+		KeyTag:      12345,
+		SignerName:  "Example.COM.",
+		Signature:   "AQID",
+	}
+	record := func(ip string) dns.RR {
+		return &dns.A{
+			Hdr: dns.RR_Header{Name: "Example.COM.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+			A:   net.ParseIP(ip),
+		}
+	}
+	// The signature data, then each record with the lower-case owner name
+	// and original TTL, ordered by their data
+	expected := hexutil.MustDecode("0x" +
+		"0001" + "08" + "02" + "00000e10" + "5e000000" + "5d000000" + "3039" + "076578616d706c6503636f6d00" +
+		"076578616d706c6503636f6d00" + "0001" + "0001" + "00000e10" + "0004" + "c0000201" +
+		"076578616d706c6503636f6d00" + "0001" + "0001" + "00000e10" + "0004" + "c0000202")
+
+	entry, err := oracleRRSet([]dns.RR{record("192.0.2.2"), record("192.0.2.1")}, sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(entry.Rrset, expected) {
+		t.Errorf("RRset is %x, expected %x", entry.Rrset, expected)
+	}
+	if !bytes.Equal(entry.Sig, []byte{1, 2, 3}) {
+		t.Errorf("signature is %x, expected 010203", entry.Sig)
+	}
+
+	// The order of the records supplied does not matter
+	entry, err = oracleRRSet([]dns.RR{record("192.0.2.1"), record("192.0.2.2")}, sig)
+	if err != nil || !bytes.Equal(entry.Rrset, expected) {
+		t.Errorf("RRset of ordered records is %x (%v), expected %x", entry.Rrset, err, expected)
+	}
+
+	// The records passed in are not changed by being made canonical
+	rr := record("192.0.2.1")
+	if _, err = oracleRRSet([]dns.RR{rr}, sig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rr.Header().Name != "Example.COM." || rr.Header().Ttl != 300 {
+		t.Errorf("record was changed to %s with TTL %d", rr.Header().Name, rr.Header().Ttl)
+	}
+
+	invalid := *sig
+	invalid.Signature = "!"
+	if _, err = oracleRRSet([]dns.RR{rr}, &invalid); err == nil {
+		t.Error("expected error for invalid signature")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// estimateOnly is set when a command is run under 'ens estimate', in which
+// case its transactions are estimated but never signed or sent
+var estimateOnly bool
+
+// estimateCmd represents the estimate command
+var estimateCmd = &cobra.Command{
+	Use:   "estimate",
+	Short: "Estimate the gas and cost of ENS transactions",
+	Long: `Estimate the gas used by and the cost of the transactions of a command without sending them.  Each command that sends transactions has an equivalent under estimate that takes the same arguments.  For example:
+
+    ens estimate address set --address=0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1 enstest.eth
+
+No passphrase is required as the transactions are not signed.  The checks that the command carries out before creating its transactions are still made, so an estimate fails wherever the command itself would fail.`,
+}
+
+// addEstimateCommands adds an equivalent of each transactional command
+// under the estimate command.  It must be called once all other commands
+// have been added
+func addEstimateCommands() {
+	RootCmd.AddCommand(estimateCmd)
+	mirrorTransactionCommands(RootCmd, estimateCmd)
+}
+
+// mirrorTransactionCommands adds estimating equivalents of the
+// transactional commands under a command, returning true if any were added
+func mirrorTransactionCommands(cmd *cobra.Command, estimateParent *cobra.Command) bool {
+	added := false
+	for _, child := range cmd.Commands() {
+		if child == estimateCmd {
+			continue
+		}
+		if child.Flags().Lookup("output-tx") != nil {
+			estimateParent.AddCommand(estimatingCommand(child))
+			added = true
+			continue
+		}
+		path := strings.TrimPrefix(child.CommandPath(), RootCmd.Name()+" ")
+		group := &cobra.Command{
+			Use:     child.Use,
+			Aliases: child.Aliases,
+			Short:   fmt.Sprintf("Estimate the transactions of 'ens %s' commands", path),
+			Long:    fmt.Sprintf("Estimate the gas and cost of the transactions of 'ens %s' commands.", path),
+		}
+		if mirrorTransactionCommands(child, group) {
+			estimateParent.AddCommand(group)
+			added = true
+		}
+	}
+	return added
+}
+
+// estimatingCommand creates a command that runs a transactional command
+// with its transactions estimated rather than sent.  The flags are shared
+// with the original command so that both parse arguments identically
+func estimatingCommand(cmd *cobra.Command) *cobra.Command {
+	path := strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()+" ")
+	estimate := &cobra.Command{
+		Use:     cmd.Use,
+		Aliases: cmd.Aliases,
+		Short:   cmd.Short,
+		Long: fmt.Sprintf(`Estimate the gas used by and the cost of the transactions of 'ens %s' without signing or sending them.  This takes the same arguments as 'ens %s'; see 'ens help %s' for details.
+
+In quiet mode this will return 0 if the transactions can be estimated, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`, path, path, path),
+		Args: cmd.Args,
+		Run: func(estimate *cobra.Command, args []string) {
+			estimateOnly = true
+			cmd.Run(estimate, args)
+		},
+	}
+	estimate.Flags().AddFlagSet(cmd.Flags())
+
+	// Commands are treated by their path, so the equivalent shares it
+	estimatePath := estimateCmd.CommandPath() + " " + path
+	for _, commands := range []map[string]bool{namelessCommands, dnsNameCommands, connectionlessCommands, untimedCommands} {
+		if commands[cmd.CommandPath()] {
+			commands[estimatePath] = true
+		}
+	}
+	return estimate
+}
@@ -0,0 +1,147 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	ensclient "github.com/orinocopay/ens/client"
+)
+
+// Exit codes for categories of failure, so that scripts can tell them
+// apart.  Other failures exit with 1
+const (
+	exitWaitTimeout       = 2
+	exitBadInput          = 3
+	exitNotFound          = 4
+	exitInsufficientFunds = 5
+	exitRevert            = 6
+	exitNetwork           = 7
+)
+
+// codedError is an error in a category of failure with its own exit code
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// badInput marks an error as caused by an invalid argument or flag
+func badInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: exitBadInput, err: err}
+}
+
+// notFound marks an error as caused by something that does not exist
+func notFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: exitNotFound, err: err}
+}
+
+// networkFailure marks an error as caused by a failure to talk to the node
+func networkFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: exitNetwork, err: err}
+}
+
+// assert exits with an exit code if a condition does not hold, reporting a
+// message unless in quiet mode
+func assert(condition bool, code int, msg string) {
+	if !condition {
+		fail(code, msg)
+	}
+}
+
+// fail exits with an exit code, reporting a message unless in quiet mode
+func fail(code int, msg string) {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+	os.Exit(code)
+}
+
+// errCheck exits with the exit code for the category of an error if it is
+// not nil, reporting it with a message unless in quiet mode
+func errCheck(err error, quiet bool, msg string) {
+	if err == nil {
+		return
+	}
+	if !quiet {
+		if timedOut(err) {
+			fmt.Fprintf(os.Stderr, "%s: timed out after %v; use --timeout to allow longer\n", msg, timeout)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
+		}
+	}
+	os.Exit(exitCode(err))
+}
+
+// exitCode returns the exit code for the category of an error
+func exitCode(err error) int {
+	var coded *codedError
+	var numErr *strconv.NumError
+	var netErr net.Error
+	var httpErr rpc.HTTPError
+	var rpcErr rpc.Error
+	// Nodes report these failures with a message and a generic error code
+	nodeErr := ""
+	if errors.As(err, &rpcErr) {
+		nodeErr = strings.ToLower(rpcErr.Error())
+	}
+	// Errors marked with a category are checked after the failures that they
+	// can wrap, so that for example a name that cannot be resolved because
+	// the node is unreachable is a network failure rather than bad input
+	switch {
+	case strings.Contains(nodeErr, "insufficient funds"):
+		return exitInsufficientFunds
+	case ensclient.RevertData(err) != nil,
+		strings.Contains(nodeErr, "execution reverted"):
+		return exitRevert
+	case errors.Is(err, ensclient.ErrNoResolver),
+		errors.Is(err, ensclient.ErrNotWrapped),
+		errors.Is(err, ethereum.NotFound),
+		errors.Is(err, ensclient.ErrNoAddress):
+		return exitNotFound
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr),
+		errors.As(err, &httpErr):
+		return exitNetwork
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.As(err, &numErr):
+		return exitBadInput
+	}
+	return 1
+}
@@ -0,0 +1,69 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	ensclient "github.com/orinocopay/ens/client"
+)
+
+// testRPCError is an error returned by a node, with optional data
+type testRPCError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *testRPCError) Error() string          { return e.msg }
+func (e *testRPCError) ErrorCode() int         { return -32000 }
+func (e *testRPCError) ErrorData() interface{} { return e.data }
+
+func TestExitCode(t *testing.T) {
+	_, numErr := strconv.Atoi("one")
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Plain", errors.New("failed"), 1},
+		{"BadInput", badInput(errors.New("invalid name")), exitBadInput},
+		{"NotFound", notFound(errors.New("no such name")), exitNotFound},
+		{"Network", networkFailure(errors.New("connection refused")), exitNetwork},
+		{"InsufficientFundsCoded", &codedError{code: exitInsufficientFunds, err: errors.New("insufficient funds")}, exitInsufficientFunds},
+		{"NumberParse", numErr, exitBadInput},
+		{"NoResolver", fmt.Errorf("lookup failed: %w", ensclient.ErrNoResolver), exitNotFound},
+		{"NotWrapped", ensclient.ErrNotWrapped, exitNotFound},
+		{"NoAddress", ensclient.ErrNoAddress, exitNotFound},
+		{"Deadline", fmt.Errorf("call failed: %w", context.DeadlineExceeded), exitNetwork},
+		// A failure to reach the node wins over the category applied by the caller
+		{"BadInputDeadline", badInput(context.DeadlineExceeded), exitNetwork},
+		{"NodeInsufficientFunds", &testRPCError{msg: "insufficient funds for gas * price + value"}, exitInsufficientFunds},
+		{"NodeReverted", &testRPCError{msg: "execution reverted"}, exitRevert},
+		{"NodeRevertData", &testRPCError{msg: "reverted", data: "0x08c379a0"}, exitRevert},
+		{"NodeOther", &testRPCError{msg: "nonce too low"}, 1},
+		{"WrappedNodeError", badInput(&testRPCError{msg: "execution reverted: bad"}), exitRevert},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := exitCode(test.err); code != test.code {
+				t.Errorf("exit code is %d, expected %d", code, test.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// formatCmd is the help topic for --format
+var formatCmd = &cobra.Command{
+	Use:   "format",
+	Short: "Format the output of commands with Go templates",
+	Long: `Commands that support --json can instead format their output with a Go template (see https://pkg.go.dev/text/template) given by --format.  For example:
+
+    ens address --format='{{.name}} resolves to {{.address}}' enstest.eth
+
+The fields available are those of the command's JSON output, and a newline is added after the output.  Commands that output a list, such as 'ens name resolve', format each entry separately.  The fields for each command are:
+
+    ens abi                 name, namehash, contenttype, abi
+    ens account import      address, path
+    ens account list        address, path
+    ens address             name, namehash, cointype, address, profile, source, gateway
+    ens auction info        name, namehash, state, deed, value, highestbid, revealdate, finalizedate, chaintime, revealtimestamp, revealin, finalizetimestamp, finalizein
+    ens auction watch       name, event, block, transactionid, account, value, status, registrationdate
+    ens availability        name, namehash, state
+    ens bid list            name, bidder, value, salt, state, sealed, revealdate, revealnow
+    ens content             name, namehash, content, contenthash, gatewayurl, gatewaystatus, contentlength
+    ens dnsname             name, dnsname
+    ens gas                 gasprice, basefee, priorityfee, setaddrgas, setaddrcost
+    ens hash                name, namehash, label, labelhash
+    ens interface get       name, namehash, interfaceid, implementer
+    ens name                address, name
+    ens name available      name, available
+    ens name check          input, normalized, valid, error, labels (label, length), registerable
+    ens name expiry         name, namehash, expiry, daysleft, graceperiod
+    ens name info           name, namehash, state, owner, wrapped (owner, fuses, fusenames, expiry), registrant, expiry, resolver, address, primaryname, profile
+    ens name resolve        name, address, error
+    ens name sign-typed     name, address, primarytype, structhash, hash, signature
+    ens nonce               address, nonce
+    ens owner get           name, namehash, owner, registrant, resolver, address, wrapped (owner, fuses, fusenames, expiry)
+    ens owner history       block, timestamp, transactionid, event, owner, registrant, resolver
+    ens owner list-names    name, roles
+    ens pubkey              name, namehash, x, y
+    ens rawinfo             name, labelhash, namehash, state, deed, registrationdate, value, highestbid, owner, resolver
+    ens resolver            name, namehash, resolver
+    ens resolver get        name, namehash, resolver, interfaces
+    ens text                name, namehash, records
+    ens version             version, goversion, networks (name, chainid, registry, baseregistrar, controller, namewrapper)
+
+Fields that are empty are omitted from the JSON output, and show as '<no value>' in templates.  Lists and maps can be used with range and index, for example:
+
+    ens text --format='{{index .records "url"}}' enstest.eth`,
+}
+
+func init() {
+	RootCmd.AddCommand(formatCmd)
+}
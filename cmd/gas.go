@@ -0,0 +1,93 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+)
+
+// setAddrGas is the typical gas used by a transaction to set the address of
+// a name on the public resolver
+const setAddrGas = 50000
+
+type gasResult struct {
+	GasPrice    string `json:"gasprice"`
+	BaseFee     string `json:"basefee,omitempty"`
+	PriorityFee string `json:"priorityfee,omitempty"`
+	SetAddrGas  uint64 `json:"setaddrgas"`
+	SetAddrCost string `json:"setaddrcost"`
+}
+
+// gasCmd represents the gas command
+var gasCmd = &cobra.Command{
+	Use:   "gas",
+	Short: "Show the current gas prices of the network",
+	Long: `Show the gas price suggested by the node, along with the base fee and suggested priority fee on networks that support EIP-1559 fees.  For example:
+
+    ens gas
+
+The suggested priority fee is the median paid over recent blocks, as used for transactions sent by this tool.  The cost of a typical transaction to set the address of a name is also shown, based on the base fee and priority fee where available and otherwise on the gas price.
+
+In quiet mode this will return 0 if the gas prices can be obtained, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+		defer cancel()
+
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		errCheck(err, quiet, "Failed to obtain suggested gas price")
+		result := &gasResult{
+			GasPrice:   etherutils.WeiToString(gasPrice, true),
+			SetAddrGas: setAddrGas,
+		}
+		fee := gasPrice
+		history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{50})
+		if err == nil && len(history.BaseFee) > 0 && history.BaseFee[len(history.BaseFee)-1].Sign() > 0 {
+			// The final base fee is that of the next block
+			baseFee := history.BaseFee[len(history.BaseFee)-1]
+			priorityFee := medianReward(history.Reward)
+			if priorityFee == nil {
+				priorityFee, err = client.SuggestGasTipCap(ctx)
+				errCheck(err, quiet, "Failed to obtain suggested priority fee")
+			}
+			result.BaseFee = etherutils.WeiToString(baseFee, true)
+			result.PriorityFee = etherutils.WeiToString(priorityFee, true)
+			fee = new(big.Int).Add(baseFee, priorityFee)
+		}
+		result.SetAddrCost = etherutils.WeiToString(new(big.Int).Mul(fee, big.NewInt(setAddrGas)), true)
+		if quiet {
+			return
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		fmt.Printf("%-18s%s\n", "Gas price:", result.GasPrice)
+		if result.BaseFee != "" {
+			fmt.Printf("%-18s%s\n", "Base fee:", result.BaseFee)
+			fmt.Printf("%-18s%s\n", "Priority fee:", result.PriorityFee)
+		}
+		fmt.Printf("%-18s%s (%d gas)\n", "Set address cost:", result.SetAddrCost, result.SetAddrGas)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(gasCmd)
+}
@@ -17,24 +17,65 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
 
+var hashLabel bool
+
+type hashResult struct {
+	Name      string `json:"name,omitempty"`
+	NameHash  string `json:"namehash,omitempty"`
+	Label     string `json:"label"`
+	LabelHash string `json:"labelhash"`
+}
+
 // hashCmd represents the hash command
 var hashCmd = &cobra.Command{
-	Use:   "hash",
-	Short: "Obtain the ENS namehash of a name",
+	Use:     "hash",
+	Aliases: []string{"namehash"},
+	Short:   "Obtain the ENS namehash of a name",
 	Long: `Obtain the ENS namehash of a name.  For example:
 
-	ens hash foo.eth
+    ens hash foo.eth
+
+With --label the labelhash of a single label is obtained instead.  For example:
 
-In quiet mode this will return 0 if the name can be hashed, otherwise 1.`,
+    ens hash --label foo
+
+In quiet mode this will return 0 if the name can be hashed, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if hashLabel {
+			assert(!strings.Contains(args[0], "."), exitBadInput, "A label cannot contain '.'")
+			labelHash := ens.LabelHash(args[0])
+			if quiet {
+				os.Exit(0)
+			} else if jsonOutput {
+				outputJSON(&hashResult{
+					Label:     args[0],
+					LabelHash: hexutil.Encode(labelHash[:]),
+				})
+			} else {
+				fmt.Println(hex.EncodeToString(labelHash[:]))
+			}
+			return
+		}
+
 		name := ens.NameHash(args[0])
 		if quiet {
 			os.Exit(0)
+		} else if jsonOutput {
+			label := strings.Split(args[0], ".")[0]
+			labelHash := ens.LabelHash(label)
+			outputJSON(&hashResult{
+				Name:      args[0],
+				NameHash:  nameHashHex(args[0]),
+				Label:     label,
+				LabelHash: hexutil.Encode(labelHash[:]),
+			})
 		} else {
 			fmt.Println(hex.EncodeToString(name[:]))
 		}
@@ -43,4 +84,6 @@ In quiet mode this will return 0 if the name can be hashed, otherwise 1.`,
 
 func init() {
 	RootCmd.AddCommand(hashCmd)
+
+	hashCmd.Flags().BoolVar(&hashLabel, "label", false, "Obtain the labelhash of a single label rather than the namehash of a name")
 }
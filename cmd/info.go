@@ -18,10 +18,8 @@ import (
 	"fmt"
 	"math/big"
 	"os"
-	"time"
 
 	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
@@ -36,12 +34,12 @@ var infoCmd = &cobra.Command{
 
     ens info enstest.eth
 
-In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
+In quiet mode this will return 0 if the domain is owned, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		if ens.DomainLevel(args[0]) == 1 {
-			state, err := ens.State(registrarContract, client, args[0])
-			cli.ErrCheck(err, quiet, "Cannot obtain info")
+			state, err := ensClient.AuctionState(commandCtx, args[0])
+			errCheck(err, quiet, "Cannot obtain info")
 			if quiet {
 				if state == "Owned" {
 					os.Exit(0)
@@ -83,42 +81,41 @@ func availableInfo(name string) {
 }
 
 func biddingInfo(name string) {
-	_, _, registrationDate, _, _, err := ens.Entry(registrarContract, client, name)
-	cli.ErrCheck(err, quiet, "Cannot obtain auction status")
-	twoDaysAgo := time.Duration(-48) * time.Hour
-	fmt.Println("Bidding until", registrationDate.Add(twoDaysAgo))
+	entry, err := ensClient.AuctionEntry(commandCtx, name)
+	errCheck(err, quiet, "Cannot obtain auction status")
+	fmt.Println("Bidding until", entry.RegistrationDate.Add(-revealPeriod))
 }
 
 func revealingInfo(name string) {
-	_, _, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
-	cli.ErrCheck(err, quiet, "Cannot obtain information for that name")
-	fmt.Println("Revealing until", registrationDate)
+	entry, err := ensClient.AuctionEntry(commandCtx, name)
+	errCheck(err, quiet, "Cannot obtain information for that name")
+	fmt.Println("Revealing until", entry.RegistrationDate)
 	// If the value is 0 then it is is minvalue instead
-	if value.Cmp(zero) == 0 {
-		value, _ = etherutils.StringToWei("0.01 ether")
+	if entry.Value.Cmp(zero) == 0 {
+		entry.Value, _ = etherutils.StringToWei("0.01 ether")
 	}
-	fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-	fmt.Println("Highest bid is", etherutils.WeiToString(highestBid, true))
+	fmt.Println("Locked value is", etherutils.WeiToString(entry.Value, true))
+	fmt.Println("Highest bid is", etherutils.WeiToString(entry.HighestBid, true))
 	// TODO number of bids revealed?
 }
 
 func wonInfo(name string) {
-	_, deedAddress, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
-	cli.ErrCheck(err, quiet, "Cannot obtain information for that name")
-	fmt.Println("Won since", registrationDate)
-	if value.Cmp(zero) == 0 {
-		value, _ = etherutils.StringToWei("0.01 ether")
+	entry, err := ensClient.AuctionEntry(commandCtx, name)
+	errCheck(err, quiet, "Cannot obtain information for that name")
+	fmt.Println("Won since", entry.RegistrationDate)
+	if entry.Value.Cmp(zero) == 0 {
+		entry.Value, _ = etherutils.StringToWei("0.01 ether")
 	}
-	fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-	fmt.Println("Highest bid was", etherutils.WeiToString(highestBid, true))
+	fmt.Println("Locked value is", etherutils.WeiToString(entry.Value, true))
+	fmt.Println("Highest bid was", etherutils.WeiToString(entry.HighestBid, true))
 
 	// Deed
-	deedContract, err := ens.DeedContract(client, &deedAddress)
-	cli.ErrCheck(err, quiet, "Failed to obtain deed contract")
+	deedContract, err := ens.DeedContract(client, &entry.Deed)
+	errCheck(err, quiet, "Failed to obtain deed contract")
 	// Deed owner
 	deedOwner, err := ens.Owner(deedContract)
-	cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
-	deedOwnerName, _ := ens.ReverseResolve(client, &deedOwner)
+	errCheck(err, quiet, "Failed to obtain deed owner")
+	deedOwnerName, _ := ensClient.Name(commandCtx, deedOwner)
 	if deedOwnerName == "" {
 		fmt.Println("Deed owner is", deedOwner.Hex())
 	} else {
@@ -127,29 +124,29 @@ func wonInfo(name string) {
 }
 
 func ownedInfo(name string) {
-	_, deedAddress, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, name)
-	cli.ErrCheck(err, quiet, "Cannot obtain information for that name")
-	fmt.Println("Owned since", registrationDate)
-	fmt.Println("Locked value is", etherutils.WeiToString(value, true))
-	fmt.Println("Highest bid was", etherutils.WeiToString(highestBid, true))
+	entry, err := ensClient.AuctionEntry(commandCtx, name)
+	errCheck(err, quiet, "Cannot obtain information for that name")
+	fmt.Println("Owned since", entry.RegistrationDate)
+	fmt.Println("Locked value is", etherutils.WeiToString(entry.Value, true))
+	fmt.Println("Highest bid was", etherutils.WeiToString(entry.HighestBid, true))
 
 	// Deed
-	deedContract, err := ens.DeedContract(client, &deedAddress)
-	cli.ErrCheck(err, quiet, "Failed to obtain deed contract")
+	deedContract, err := ens.DeedContract(client, &entry.Deed)
+	errCheck(err, quiet, "Failed to obtain deed contract")
 	// Deed owner
-	deedOwner, err := deedContract.Owner(nil)
-	cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
-	deedOwnerName, _ := ens.ReverseResolve(client, &deedOwner)
+	deedOwner, err := deedContract.Owner(callOpts())
+	errCheck(err, quiet, "Failed to obtain deed owner")
+	deedOwnerName, _ := ensClient.Name(commandCtx, deedOwner)
 	if deedOwnerName == "" {
 		fmt.Println("Deed owner is", deedOwner.Hex())
 	} else {
 		fmt.Printf("Deed owner is %s (%s)\n", deedOwnerName, deedOwner.Hex())
 	}
 
-	previousDeedOwner, err := deedContract.PreviousOwner(nil)
-	cli.ErrCheck(err, quiet, "Failed to obtain deed owner")
+	previousDeedOwner, err := deedContract.PreviousOwner(callOpts())
+	errCheck(err, quiet, "Failed to obtain deed owner")
 	if bytes.Compare(previousDeedOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0 {
-		previousDeedOwnerName, _ := ens.ReverseResolve(client, &previousDeedOwner)
+		previousDeedOwnerName, _ := ensClient.Name(commandCtx, previousDeedOwner)
 		if previousDeedOwnerName == "" {
 			fmt.Println("Previous deed owner is", previousDeedOwner.Hex())
 		} else {
@@ -158,15 +155,13 @@ func ownedInfo(name string) {
 	}
 
 	// Address owner
-	registry, err := ens.RegistryContract(client)
-	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-	domainOwnerAddress, err := registry.Owner(nil, ens.NameHash(name))
-	cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
+	domainOwnerAddress, err := registryContract.Owner(callOpts(), ens.NameHash(name))
+	errCheck(err, quiet, "Failed to obtain domain owner")
 	if domainOwnerAddress == ens.UnknownAddress {
 		fmt.Println("Address owner not set")
 		return
 	}
-	domainOwnerName, _ := ens.ReverseResolve(client, &domainOwnerAddress)
+	domainOwnerName, _ := ensClient.Name(commandCtx, domainOwnerAddress)
 	if domainOwnerName == "" {
 		fmt.Println("Address owner is", domainOwnerAddress.Hex())
 	} else {
@@ -174,12 +169,12 @@ func ownedInfo(name string) {
 	}
 
 	// Resolver
-	resolverAddress, err := ens.Resolver(registry, name)
+	resolverAddress, err := ens.Resolver(registryContract, name)
 	if err != nil {
 		fmt.Println("Resolver not configured")
 		return
 	}
-	resolverName, _ := ens.ReverseResolve(client, &resolverAddress)
+	resolverName, _ := ensClient.Name(commandCtx, resolverAddress)
 	if resolverName == "" {
 		fmt.Println("Resolver is", resolverAddress.Hex())
 	} else {
@@ -187,7 +182,7 @@ func ownedInfo(name string) {
 	}
 
 	// Address
-	address, err := ens.Resolve(client, name)
+	address, err := resolveAddress(name)
 	if err != nil || address == ens.UnknownAddress {
 		fmt.Println("Name does not resolve to an address")
 		return
@@ -195,7 +190,7 @@ func ownedInfo(name string) {
 	fmt.Println("Domain resolves to", address.Hex())
 
 	// Reverse resolution
-	reverseDomain, err := ens.ReverseResolve(client, &address)
+	reverseDomain, err := ensClient.Name(commandCtx, address)
 	if err != nil || reverseDomain == "" {
 		fmt.Println("Address does not resolve to a domain")
 		return
@@ -207,15 +202,13 @@ func ownedInfo(name string) {
 
 func subdomainInfo(name string) {
 	// Address owner
-	registry, err := ens.RegistryContract(client)
-	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-	domainOwnerAddress, err := registry.Owner(nil, ens.NameHash(name))
-	cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
+	domainOwnerAddress, err := registryContract.Owner(callOpts(), ens.NameHash(name))
+	errCheck(err, quiet, "Failed to obtain domain owner")
 	if domainOwnerAddress == ens.UnknownAddress {
 		fmt.Println("Address owner not set")
 		return
 	}
-	domainOwnerName, _ := ens.ReverseResolve(client, &domainOwnerAddress)
+	domainOwnerName, _ := ensClient.Name(commandCtx, domainOwnerAddress)
 	if domainOwnerName == "" {
 		fmt.Println("Address owner is", domainOwnerAddress.Hex())
 	} else {
@@ -223,12 +216,12 @@ func subdomainInfo(name string) {
 	}
 
 	// Resolver
-	resolverAddress, err := ens.Resolver(registry, name)
+	resolverAddress, err := ens.Resolver(registryContract, name)
 	if err != nil {
 		fmt.Println("Resolver not configured")
 		return
 	}
-	resolverName, _ := ens.ReverseResolve(client, &resolverAddress)
+	resolverName, _ := ensClient.Name(commandCtx, resolverAddress)
 	if resolverName == "" {
 		fmt.Println("Resolver is", resolverAddress.Hex())
 	} else {
@@ -236,7 +229,7 @@ func subdomainInfo(name string) {
 	}
 
 	// Address
-	address, err := ens.Resolve(client, name)
+	address, err := resolveAddress(name)
 	if err != nil || address == ens.UnknownAddress {
 		fmt.Println("Name does not resolve to an address")
 		return
@@ -244,7 +237,7 @@ func subdomainInfo(name string) {
 	fmt.Println("Domain resolves to", address.Hex())
 
 	// Reverse resolution
-	reverseDomain, err := ens.ReverseResolve(client, &address)
+	reverseDomain, err := ensClient.Name(commandCtx, address)
 	if err != nil || reverseDomain == "" {
 		fmt.Println("Address does not resolve to a domain")
 		return
@@ -0,0 +1,50 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// interfaceCmd represents the interface command
+var interfaceCmd = &cobra.Command{
+	Use:   "interface",
+	Short: "Manage interface records of ENS names",
+	Long:  `Obtain and set the contracts implementing interfaces (EIP-1844) for names in the Ethereum Name Service.`,
+}
+
+// parseInterfaceID parses a 4-byte interface ID given as 0x-prefixed hex
+func parseInterfaceID(input string) ([4]byte, error) {
+	var interfaceID [4]byte
+	if !strings.HasPrefix(input, "0x") {
+		return interfaceID, errors.New("interface ID must be 0x-prefixed hex")
+	}
+	data, err := hex.DecodeString(input[2:])
+	if err != nil {
+		return interfaceID, err
+	}
+	if len(data) != 4 {
+		return interfaceID, errors.New("interface ID must be 4 bytes")
+	}
+	copy(interfaceID[:], data)
+	return interfaceID, nil
+}
+
+func init() {
+	RootCmd.AddCommand(interfaceCmd)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+var interfaceGetID string
+
+type interfaceGetResult struct {
+	Name        string `json:"name"`
+	NameHash    string `json:"namehash"`
+	InterfaceID string `json:"interfaceid"`
+	Implementer string `json:"implementer"`
+}
+
+// interfaceGetCmd represents the interface get command
+var interfaceGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain the implementer of an interface for an ENS name",
+	Long: `Obtain the address of the contract implementing an interface (EIP-1844) for a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens interface get --id=0x36372b07 enstest.eth
+
+The interface is given by its 4-byte ERC-165 interface ID, for example 0x36372b07 for ERC-20.  If no interface record is set then resolvers return the address of the name if it supports the interface itself.
+
+In quiet mode this will return 0 if the name has an implementer of the interface, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		interfaceID, err := parseInterfaceID(interfaceGetID)
+		errCheck(badInput(err), quiet, "Invalid interface ID")
+
+		implementer, err := ensClient.InterfaceImplementer(commandCtx, args[0], interfaceID)
+		errCheck(err, quiet, "Failed to obtain interface implementer")
+		assert(implementer != ens.UnknownAddress, exitNotFound, "No implementer of that interface for that name")
+		if quiet {
+			return
+		}
+		if jsonOutput {
+			outputJSON(&interfaceGetResult{
+				Name:        args[0],
+				NameHash:    nameHashHex(args[0]),
+				InterfaceID: interfaceGetID,
+				Implementer: implementer.Hex(),
+			})
+			return
+		}
+		fmt.Println(implementer.Hex())
+	},
+}
+
+func init() {
+	interfaceCmd.AddCommand(interfaceGetCmd)
+
+	interfaceGetCmd.Flags().StringVar(&interfaceGetID, "id", "", "Interface ID as 4 bytes of 0x-prefixed hex")
+}
@@ -0,0 +1,80 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var interfaceSetID string
+var interfaceSetImplementerStr string
+
+// interfaceSetCmd represents the interface set command
+var interfaceSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the implementer of an interface for an ENS name",
+	Long: `Set the address of the contract implementing an interface (EIP-1844) for a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens interface set --id=0x36372b07 --implementer=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" enstest.eth
+
+The interface is given by its 4-byte ERC-165 interface ID.  The implementer can be an address or an ENS name; an implementer of 0x0000000000000000000000000000000000000000 removes the record.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the interface record is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		interfaceID, err := parseInterfaceID(interfaceSetID)
+		errCheck(badInput(err), quiet, "Invalid interface ID")
+		assert(interfaceSetImplementerStr != "", exitBadInput, "Implementer is required")
+		var implementer common.Address
+		if common.IsHexAddress(interfaceSetImplementerStr) {
+			implementer = common.HexToAddress(interfaceSetImplementerStr)
+		} else {
+			implementer, err = resolveAddress(interfaceSetImplementerStr)
+			errCheck(badInput(err), quiet, "Invalid implementer")
+		}
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		tx, err := ensClient.SetInterface(commandCtx, opts, args[0], interfaceID, implementer)
+		errCheck(err, quiet, "Failed to set interface record for that name")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"interfaceid": interfaceSetID,
+			"implementer": implementer.Hex()}, "Interface set")
+	},
+}
+
+func init() {
+	interfaceCmd.AddCommand(interfaceSetCmd)
+
+	interfaceSetCmd.Flags().StringVar(&interfaceSetID, "id", "", "Interface ID as 4 bytes of 0x-prefixed hex")
+	interfaceSetCmd.Flags().StringVarP(&interfaceSetImplementerStr, "implementer", "i", "", "Address or name of the contract implementing the interface")
+	addTransactionFlags(interfaceSetCmd, "Passphrase for the account that owns the name")
+}
@@ -14,12 +14,6 @@
 package cmd
 
 import (
-	"fmt"
-	"math/big"
-
-	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -34,39 +28,27 @@ var invalidateCmd = &cobra.Command{
 
     ens invalidate --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" bad.eth
 
-In quiet mode this will return 0 if the invalidate transaction has been submitted, otherwise 1.`,
+In quiet mode this will return 0 if the invalidate transaction has been submitted, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		// Ensure that the name is in a suitable state
-		registrarContract, err := ens.RegistrarContract(client)
-		state, err := ens.State(registrarContract, client, args[0])
-		cli.Assert(state == "Won" || state == "Owned", quiet, "Name not in a suitable state to invalidate")
+		state, err := ensClient.AuctionState(commandCtx, args[0])
+		assert(state == "Won" || state == "Owned", exitBadInput, "Name not in a suitable state to invalidate")
 
 		// Fetch the wallet and account for the address
-		invalidateAddress, err := ens.Resolve(client, invalidateAddressStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain invalidate address")
-		wallet, err := cli.ObtainWallet(chainID, invalidateAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain a wallet for the address")
-		account, err := cli.ObtainAccount(&wallet, &invalidateAddress, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain an account for the address")
+		invalidateAddress, err := resolveAddress(invalidateAddressStr)
+		errCheck(err, quiet, "Failed to obtain invalidate address")
+		wallet, account, err := obtainWalletAndAccount(invalidateAddress, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the address")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
-		tx, err := ens.InvalidateName(session, args[0])
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0]}).Info("Invalidate")
+		tx, err := ensClient.InvalidateName(commandCtx, opts, args[0])
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0]}, "Invalidate")
 
 	},
 }
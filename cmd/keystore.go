@@ -0,0 +1,71 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+var keystoreDir string
+
+// keystorePath returns the keystore directory given by --keystore or
+// $ETH_KEYSTORE, or an empty string if neither is set
+func keystorePath() string {
+	if keystoreDir != "" {
+		return keystoreDir
+	}
+	return os.Getenv("ETH_KEYSTORE")
+}
+
+// openKeystore opens the keystore in a directory
+func openKeystore(dir string) (*keystore.KeyStore, error) {
+	dir, err := homedir.Expand(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("keystore %s cannot be read: %v", dir, err)
+	}
+	return keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP), nil
+}
+
+// obtainKeystoreWalletAndAccount obtains the wallet and account for an
+// address from the keystore in a directory, checking that the passphrase
+// unlocks the account
+func obtainKeystoreWalletAndAccount(dir string, address common.Address, passphrase string) (accounts.Wallet, *accounts.Account, error) {
+	ks, err := openKeystore(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err := ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, nil, notFound(fmt.Errorf("account %s not found in keystore %s", address.Hex(), dir))
+	}
+	if err = ks.Unlock(account, passphrase); err != nil {
+		return nil, nil, err
+	}
+	ks.Lock(account.Address)
+	for _, wallet := range ks.Wallets() {
+		if wallet.Contains(account) {
+			return wallet, &account, nil
+		}
+	}
+	return nil, nil, notFound(fmt.Errorf("account %s not found in keystore %s", address.Hex(), dir))
+}
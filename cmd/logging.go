@@ -0,0 +1,87 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var verbosity string
+
+// configureLogging sets the level and destination of the log.  Logs go to
+// stderr unless --log-file is supplied, in which case they are written to
+// the file as JSON.  The log file records transactions, so defaults to info
+// rather than warn
+func configureLogging(cmd *cobra.Command) error {
+	level, err := log.ParseLevel(verbosity)
+	if err != nil {
+		return err
+	}
+	if logFile != "" && !cmd.Flags().Changed("verbosity") {
+		level = log.InfoLevel
+	}
+	log.SetLevel(level)
+
+	if logFile == "" {
+		log.SetOutput(os.Stderr)
+		return nil
+	}
+	f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	log.SetOutput(f)
+	log.SetFormatter(&log.JSONFormatter{})
+	return nil
+}
+
+// loggingTransport logs HTTP requests to an Ethereum node and their
+// responses at debug level
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends a request, logging the request and response bodies
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !log.IsLevelEnabled(log.DebugLevel) {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			log.WithFields(log.Fields{"url": req.URL.Redacted(), "request": string(data)}).Debug("RPC request")
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		log.WithFields(log.Fields{"url": req.URL.Redacted(), "error": err}).Debug("RPC request failed")
+		return resp, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	log.WithFields(log.Fields{"url": req.URL.Redacted(), "status": resp.StatusCode, "response": string(data)}).Debug("RPC response")
+	return resp, nil
+}
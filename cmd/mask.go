@@ -0,0 +1,63 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/spf13/cobra"
+)
+
+var bidMaskMaxStr string
+
+// obtainBidMask returns the mask for a bid, which is the value sent with
+// the bid to hide its true value.  If no mask is supplied a random one is
+// picked between the bid and --mask-max, which defaults to twice the bid.
+// The excess of the mask over the bid is refunded when the bid is revealed
+func obtainBidMask(maskStr string, bidPrice *big.Int) *big.Int {
+	if maskStr != "" {
+		mask, err := etherutils.StringToWei(maskStr)
+		errCheck(badInput(err), quiet, "Invalid mask")
+		assert(mask.Cmp(bidPrice) >= 0, exitBadInput, fmt.Sprintf("Mask %s is less than the bid %s; the mask is the amount sent with the bid so must be at least the bid", etherutils.WeiToString(mask, true), etherutils.WeiToString(bidPrice, true)))
+		if mask.Cmp(bidPrice) == 0 && !quiet {
+			fmt.Fprintln(os.Stderr, "WARNING: mask is the same as the bid, so anyone watching can see the value of the bid before it is revealed")
+		}
+		return mask
+	}
+
+	maxMask := new(big.Int).Mul(bidPrice, big.NewInt(2))
+	if bidMaskMaxStr != "" {
+		var err error
+		maxMask, err = etherutils.StringToWei(bidMaskMaxStr)
+		errCheck(badInput(err), quiet, "Invalid maximum mask")
+		assert(maxMask.Cmp(bidPrice) >= 0, exitBadInput, "Maximum mask must be at least the bid")
+	}
+	spread, err := rand.Int(rand.Reader, new(big.Int).Add(new(big.Int).Sub(maxMask, bidPrice), big.NewInt(1)))
+	errCheck(err, quiet, "Failed to generate mask")
+	mask := new(big.Int).Add(bidPrice, spread)
+	if !quiet {
+		fmt.Println("Mask:", etherutils.WeiToString(mask, true))
+		fmt.Println("The mask is picked at random between the bid and", etherutils.WeiToString(maxMask, true), "to hide the value of the bid; a higher maximum hides it better but ties up more Ether until the bid is revealed, when the excess is refunded")
+	}
+	return mask
+}
+
+// addBidMaskMaxFlag adds the flag for the maximum of a randomly picked mask
+func addBidMaskMaxFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&bidMaskMaxStr, "mask-max", "", "Maximum of the random mask picked when --mask is not supplied (defaults to twice the bid)")
+}
@@ -0,0 +1,79 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"math/big"
+	"testing"
+)
+
+// maskPicks is the number of random masks checked against their bounds
+const maskPicks = 100
+
+func TestObtainBidMaskSupplied(t *testing.T) {
+	quiet = true
+	defer func() { quiet = false }()
+
+	bid := big.NewInt(1000)
+	if mask := obtainBidMask("3000 Wei", bid); mask.Cmp(big.NewInt(3000)) != 0 {
+		t.Errorf("mask is %v, expected 3000", mask)
+	}
+	// A mask equal to the bid is allowed, with a warning
+	if mask := obtainBidMask("1000 Wei", bid); mask.Cmp(bid) != 0 {
+		t.Errorf("mask is %v, expected 1000", mask)
+	}
+}
+
+func TestObtainBidMaskRandom(t *testing.T) {
+	quiet = true
+	defer func() { quiet = false }()
+
+	bid := big.NewInt(1000)
+	maxMask := big.NewInt(2000)
+	seenAbove := false
+	for i := 0; i < maskPicks; i++ {
+		mask := obtainBidMask("", bid)
+		if mask.Cmp(bid) < 0 || mask.Cmp(maxMask) > 0 {
+			t.Fatalf("mask %v is outside of the bid %v to twice the bid %v", mask, bid, maxMask)
+		}
+		if mask.Cmp(bid) > 0 {
+			seenAbove = true
+		}
+	}
+	if !seenAbove {
+		t.Errorf("no mask of %d picks was above the bid", maskPicks)
+	}
+}
+
+func TestObtainBidMaskMax(t *testing.T) {
+	quiet = true
+	bidMaskMaxStr = "1500 Wei"
+	defer func() {
+		quiet = false
+		bidMaskMaxStr = ""
+	}()
+
+	bid := big.NewInt(1000)
+	for i := 0; i < maskPicks; i++ {
+		if mask := obtainBidMask("", bid); mask.Cmp(bid) < 0 || mask.Cmp(big.NewInt(1500)) > 0 {
+			t.Fatalf("mask %v is outside of the bid to --mask-max", mask)
+		}
+	}
+
+	// A maximum equal to the bid leaves no room for the mask to move
+	bidMaskMaxStr = "1000 Wei"
+	if mask := obtainBidMask("", bid); mask.Cmp(bid) != 0 {
+		t.Errorf("mask is %v, expected the bid", mask)
+	}
+}
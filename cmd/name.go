@@ -17,26 +17,38 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
 
+type nameResult struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
 // nameCmd represents the name command
 var nameCmd = &cobra.Command{
 	Use:   "name",
 	Short: "Obtain the ENS name of an address",
 	Long: `Obtain the name registered with the Ethereum Name Service (ENS) for an address.  For example:
 
-	ens name 0xe40626310e0726e45041ac34094037f30d2a9cc3
+    ens name 0xe40626310e0726e45041ac34094037f30d2a9cc3
 
-In quiet mode this will return 0 if the address resolves correctly, otherwise 1.`,
+In quiet mode this will return 0 if the address resolves correctly, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
+		assert(common.IsHexAddress(args[0]), exitBadInput, "Invalid address")
 		address := common.HexToAddress(args[0])
-		name, err := ens.ReverseResolve(client, &address)
-		cli.ErrCheck(err, quiet, "Failed to obtain name")
+		name, err := ensClient.Name(commandCtx, address)
+		errCheck(err, quiet, "Failed to obtain name")
+		assert(name != "", exitNotFound, "No name set for that address")
 		if !quiet {
-			fmt.Println(name)
+			if jsonOutput {
+				outputJSON(&nameResult{
+					Address: address.Hex(),
+					Name:    name,
+				})
+			} else {
+				fmt.Println(name)
+			}
 		}
 	},
 }
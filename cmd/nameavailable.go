@@ -0,0 +1,76 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type nameAvailableResult struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+}
+
+// nameAvailableCmd represents the name available command
+var nameAvailableCmd = &cobra.Command{
+	Use:   "available",
+	Short: "Check if an ENS name can be registered",
+	Long: `Check if a name can be registered with the Ethereum Name Service (ENS) permanent registrar.  For example:
+
+    ens name available enstest.eth
+
+Names must be directly under .eth and have at least 3 characters.  Names with fewer than 5 characters cost considerably more, so if such a name is available a warning with its yearly price is shown.
+
+In quiet mode this will return 0 if the name is available, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Only names directly under .eth can be registered")
+		label, err := ens.Domain(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+		assert(utf8.RuneCountInString(label) >= minNameLength, exitBadInput, fmt.Sprintf("Names must have at least %d characters", minNameLength))
+
+		isAvailable, err := ensClient.Available(commandCtx, label)
+		errCheck(err, quiet, "Failed to obtain availability")
+		if isAvailable && !quiet {
+			warning, err := shortNameWarning(label)
+			errCheck(err, quiet, "Failed to obtain price")
+			if warning != "" {
+				fmt.Fprintln(os.Stderr, "WARNING:", warning)
+			}
+		}
+
+		if quiet {
+			if isAvailable {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+		if jsonOutput {
+			outputJSON(&nameAvailableResult{
+				Name:      args[0],
+				Available: isAvailable,
+			})
+		} else {
+			fmt.Println(isAvailable)
+		}
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameAvailableCmd)
+}
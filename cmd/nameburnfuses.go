@@ -0,0 +1,82 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameBurnFusesFuses []string
+
+// nameBurnFusesCmd represents the name burn-fuses command
+var nameBurnFusesCmd = &cobra.Command{
+	Use:   "burn-fuses",
+	Short: "Burn fuses of a wrapped ENS name",
+	Long: `Burn fuses of a name registered with the Ethereum Name Service (ENS) and wrapped with the NameWrapper, removing permissions from the name.  For example:
+
+    ens name burn-fuses --fuses=CANNOT_UNWRAP,CANNOT_SET_RESOLVER --passphrase="my secret passphrase" enstest.eth
+
+The fuses that can be burned by the owner of a name are CANNOT_UNWRAP, CANNOT_BURN_FUSES, CANNOT_TRANSFER, CANNOT_SET_RESOLVER, CANNOT_SET_TTL, CANNOT_CREATE_SUBDOMAIN and CANNOT_APPROVE.  CANNOT_UNWRAP must be burned before or with any other fuse, and can only be burned once the owner of the parent has burned PARENT_CANNOT_CONTROL; this is always the case for names directly under .eth.  The current fuses of a name are shown by 'ens owner get'.
+
+Burning fuses is irreversible: burned fuses cannot be restored until the name expires.
+
+The keystore for the account that owns the wrapped name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to burn the fuses is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(nameBurnFusesFuses) > 0, exitBadInput, "Fuses to burn are required")
+		assert(ensClient.NameWrapper() != ens.UnknownAddress, exitNotFound, "There is no NameWrapper on this network")
+		fuses, err := ensclient.ParseFuses(nameBurnFusesFuses)
+		errCheck(badInput(err), quiet, "Invalid fuses")
+
+		data, err := ensClient.WrappedData(commandCtx, args[0])
+		if err == ensclient.ErrNotWrapped {
+			fail(exitNotFound, "Name is not wrapped")
+		}
+		errCheck(err, quiet, "Failed to obtain wrapped name")
+		assert(data.Owner != ens.UnknownAddress, exitNotFound, "Wrapped name has no owner; it might have expired")
+		assert(fuses&^data.Fuses != 0, exitBadInput, "Fuses are already burned")
+		errCheck(ensclient.CheckFuseBurn(data.Fuses, fuses), quiet, "Fuses cannot be burned")
+
+		if !quiet {
+			fmt.Println("Burning:", fuseDescription(fuses&^data.Fuses))
+			fmt.Fprintln(os.Stderr, "Warning: burned fuses cannot be restored until the name expires")
+		}
+
+		wallet, account, err := obtainWalletAndAccount(data.Owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the wrapped name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.BurnFuses(commandCtx, opts, args[0], fuses)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"fuses": fuses}, "Fuse burn")
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameBurnFusesCmd)
+
+	nameBurnFusesCmd.Flags().StringSliceVar(&nameBurnFusesFuses, "fuses", nil, "Comma-separated fuses to burn")
+	addTransactionFlags(nameBurnFusesCmd, "Passphrase for the account that owns the wrapped name")
+}
@@ -0,0 +1,104 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type nameCheckLabel struct {
+	Label  string `json:"label"`
+	Length int    `json:"length"`
+}
+
+type nameCheckResult struct {
+	Input        string            `json:"input"`
+	Normalized   string            `json:"normalized,omitempty"`
+	Valid        bool              `json:"valid"`
+	Error        string            `json:"error,omitempty"`
+	Labels       []*nameCheckLabel `json:"labels,omitempty"`
+	Registerable bool              `json:"registerable"`
+}
+
+// nameCheckCmd represents the name check command
+var nameCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check if an ENS name is valid",
+	Long: `Check if a name is valid for the Ethereum Name Service (ENS), showing the form to which it normalizes.  For example:
+
+    ens name check EnsTest.eth
+
+Names are normalized with UTS-46, which lowercases them and maps equivalent characters to a single form; names that contain disallowed characters are invalid.  This is not the full ENSIP-15 normalization used by current ENS clients: emoji sequences, confusable characters and mixed scripts are not checked, so a name that is valid here can be rejected or normalized differently by those clients.  The number of characters in each label is shown, and whether the name could be registered with the permanent registrar (it must be directly under .eth with at least 3 characters).  Check the normalized form carefully: characters that look alike can normalize to different names.
+
+In quiet mode this will return 0 if the name is valid, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 1 && args[0] != "", exitBadInput, "This command requires a name")
+		result := &nameCheckResult{
+			Input: args[0],
+		}
+		normalized, err := cleanName(args[0])
+		if err == nil {
+			normalized, err = uts46Normalize(expandName(normalized))
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Valid = true
+			result.Normalized = normalized
+			for _, label := range strings.Split(normalized, ".") {
+				result.Labels = append(result.Labels, &nameCheckLabel{
+					Label:  label,
+					Length: utf8.RuneCountInString(label),
+				})
+				if label == "" {
+					result.Valid = false
+					result.Error = "empty label"
+				}
+			}
+			result.Registerable = result.Valid && ens.DomainLevel(normalized) == 1 && result.Labels[0].Length >= minNameLength
+		}
+
+		if !quiet {
+			if jsonOutput {
+				outputJSON(result)
+			} else {
+				fmt.Printf("%-18s%s\n", "Input:", result.Input)
+				if result.Normalized != "" {
+					fmt.Printf("%-18s%s\n", "Normalized:", result.Normalized)
+				}
+				fmt.Printf("%-18s%v\n", "Valid:", result.Valid)
+				if result.Error != "" {
+					fmt.Printf("%-18s%s\n", "Error:", result.Error)
+				}
+				for _, label := range result.Labels {
+					fmt.Printf("%-18s%s (%d characters)\n", "Label:", label.Label, label.Length)
+				}
+				fmt.Printf("%-18s%v\n", "Registerable:", result.Registerable)
+			}
+		}
+		if !result.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameCheckCmd)
+}
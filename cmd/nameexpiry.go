@@ -0,0 +1,85 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type nameExpiryResult struct {
+	Name        string `json:"name"`
+	NameHash    string `json:"namehash"`
+	Expiry      string `json:"expiry"`
+	DaysLeft    int    `json:"daysleft"`
+	GracePeriod bool   `json:"graceperiod"`
+}
+
+// nameExpiryCmd represents the name expiry command
+var nameExpiryCmd = &cobra.Command{
+	Use:   "expiry",
+	Short: "Obtain the expiry of an ENS name",
+	Long: `Obtain the time at which the registration of a name with the Ethereum Name Service (ENS) expires.  For example:
+
+    ens name expiry enstest.eth
+
+After expiry a name enters a grace period of 90 days during which it can only be renewed.
+
+In quiet mode this will return 0 if the name is registered and has not expired, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Only names directly under .eth have an expiry")
+		expiry, err := ensClient.Expiry(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain expiry")
+		assert(!expiry.IsZero(), exitNotFound, "Name is not registered")
+
+		now := time.Now()
+		expired := !now.Before(expiry)
+		if quiet {
+			if expired {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		daysLeft := int(expiry.Sub(now).Hours() / 24)
+		inGracePeriod := expired && now.Before(expiry.Add(gracePeriod))
+		if jsonOutput {
+			outputJSON(&nameExpiryResult{
+				Name:        args[0],
+				NameHash:    nameHashHex(args[0]),
+				Expiry:      expiry.UTC().Format(time.RFC3339),
+				DaysLeft:    daysLeft,
+				GracePeriod: inGracePeriod,
+			})
+			return
+		}
+		fmt.Printf("%-18s%s (%s)\n", "Expiry:", expiry.UTC().Format(time.RFC1123), expiry.Local().Format(time.RFC1123))
+		switch {
+		case !expired:
+			fmt.Printf("%-18s%d\n", "Days left:", daysLeft)
+		case inGracePeriod:
+			fmt.Printf("%-18s%s\n", "State:", "Expired; in grace period until "+expiry.Add(gracePeriod).UTC().Format(time.RFC1123))
+		default:
+			fmt.Printf("%-18s%s\n", "State:", "Expired")
+		}
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameExpiryCmd)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameImportDNSAddressStr string
+
+// nameImportDNSCmd represents the name import-dns command
+var nameImportDNSCmd = &cobra.Command{
+	Use:   "import-dns",
+	Short: "Import a DNS name in to ENS",
+	Long: `Import a DNS name in to the Ethereum Name Service (ENS) using a DNSSEC proof of its ownership.  For example:
+
+    ens name import-dns --passphrase="my secret passphrase" example.xyz
+
+The name must have DNSSEC enabled and a TXT record for _ens.<name> containing 'a=<address>', for example _ens.example.xyz with 'a=0x5FfC014343cd971B7eb70732021E26C35B744cc4'.  The name is claimed in ENS for that address.  The chain of DNSSEC records from the root zone down to the TXT record is fetched from the DNS server given by --dns-server and submitted to the DNS registrar for the name's TLD, which checks it with the DNSSEC oracle.  The TLD must be enabled for DNS names in ENS.
+
+The proof can be submitted by any account; by default it is sent by the address in the TXT record.  The keystore for the sending address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to import the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimSuffix(args[0], ".")
+		parts := strings.Split(name, ".")
+		assert(len(parts) >= 2, exitBadInput, "Top-level names cannot be imported")
+		tld := parts[len(parts)-1]
+		assert(tld != "eth", exitBadInput, "Names under .eth are registered rather than imported")
+
+		// The DNS registrar for the TLD owns it in the registry
+		registrarAddress, err := ensClient.Owner(commandCtx, tld)
+		errCheck(err, quiet, "Failed to obtain owner of TLD")
+		assert(registrarAddress != ens.UnknownAddress, exitNotFound, fmt.Sprintf("TLD %s is not enabled for DNS names in ENS", tld))
+
+		proof, rrs, err := dnssecProof("_ens."+name, dns.TypeTXT)
+		errCheck(err, quiet, "Failed to obtain DNSSEC proof")
+		owner, err := dnsClaimedOwner(rrs)
+		errCheck(err, quiet, fmt.Sprintf("Failed to obtain owner from _ens.%s", name))
+		if !quiet {
+			fmt.Println("Owner in DNS is", owner.Hex())
+			fmt.Printf("Proof has %d signed record sets\n", len(proof))
+		}
+		currentOwner, err := ensClient.Owner(commandCtx, name)
+		errCheck(err, quiet, "Failed to obtain current owner")
+		if !quiet && currentOwner != ens.UnknownAddress {
+			fmt.Println("Current owner in ENS is", currentOwner.Hex())
+		}
+
+		sender := owner
+		if nameImportDNSAddressStr != "" {
+			sender, err = resolveAddress(nameImportDNSAddressStr)
+			errCheck(err, quiet, "Failed to obtain sending address")
+		}
+		wallet, account, err := obtainWalletAndAccount(sender, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the sending address")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.ImportDNSName(commandCtx, opts, name, proof)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": name,
+			"owner": owner.Hex()}, "Name import DNS")
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameImportDNSCmd)
+
+	nameImportDNSCmd.Flags().StringVarP(&nameImportDNSAddressStr, "address", "a", "", "Address sending the proof (defaults to the owner in DNS)")
+	nameImportDNSCmd.Flags().StringVar(&dnsServer, "dns-server", "1.1.1.1:53", "DNS server from which to obtain DNSSEC records, as host:port")
+	addTransactionFlags(nameImportDNSCmd, "Passphrase for the sending address")
+}
@@ -0,0 +1,153 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type nameInfoResult struct {
+	Name        string            `json:"name"`
+	NameHash    string            `json:"namehash"`
+	State       string            `json:"state"`
+	Owner       string            `json:"owner,omitempty"`
+	Wrapped     *ownerGetWrapped  `json:"wrapped,omitempty"`
+	Registrant  string            `json:"registrant,omitempty"`
+	Expiry      string            `json:"expiry,omitempty"`
+	Resolver    string            `json:"resolver,omitempty"`
+	Address     string            `json:"address,omitempty"`
+	PrimaryName string            `json:"primaryname,omitempty"`
+	Profile     map[string]string `json:"profile,omitempty"`
+}
+
+// nameInfoCmd represents the name info command
+var nameInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Obtain everything about an ENS name",
+	Long: `Obtain the state, owners, expiry, resolver, address, primary name and profile of a name registered with the Ethereum Name Service (ENS) in one go.  For example:
+
+    ens name info enstest.eth
+
+The primary name is the name that the name's address resolves back to, which need not be the name itself.  The profile is the avatar, url, com.twitter and description text records, where set.  Details that cannot be obtained, for example because the name has no resolver, are left out.
+
+In quiet mode this will return 0 if the name has an owner, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		if quiet {
+			if owner == ens.UnknownAddress {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		result := &nameInfoResult{
+			Name:     args[0],
+			NameHash: nameHashHex(args[0]),
+			State:    "Available",
+		}
+		if owner != ens.UnknownAddress {
+			result.State = "Owned"
+			result.Owner = owner.Hex()
+		}
+		// Only names directly under .eth are registered with an expiry;
+		// other names directly under a top-level domain are DNS names
+		registered := ens.DomainLevel(args[0]) == 1 && strings.HasSuffix(args[0], ".eth")
+		if registered {
+			expiry, err := ensClient.Expiry(commandCtx, args[0])
+			errCheck(err, quiet, "Failed to obtain expiry")
+			now, err := chainTime()
+			errCheck(err, quiet, "Failed to obtain latest block")
+			result.State = registrationState(expiry, now)
+			if !expiry.IsZero() {
+				result.Expiry = expiry.UTC().Format(time.RFC3339)
+			}
+			// The registrant is unavailable if the name has expired
+			if registrantAddress, err := ensClient.Registrant(commandCtx, args[0]); err == nil {
+				result.Registrant = registrantAddress.Hex()
+			}
+		}
+		if owner != ens.UnknownAddress && owner == ensClient.NameWrapper() {
+			result.Wrapped, err = wrappedInfo(args[0])
+			errCheck(err, quiet, "Cannot obtain wrapped name")
+		}
+		if resolverAddress, err := ensClient.Resolver(commandCtx, args[0]); err == nil && resolverAddress != ens.UnknownAddress {
+			result.Resolver = resolverAddress.Hex()
+		}
+		if address, err := resolveAddress(args[0]); err == nil && address != ens.UnknownAddress {
+			result.Address = address.Hex()
+			if primaryName, err := ensClient.Name(commandCtx, address); err == nil {
+				result.PrimaryName = primaryName
+			}
+		}
+		if result.Resolver != "" {
+			if profile, err := nameProfile(args[0]); err == nil && len(profile) > 0 {
+				result.Profile = profile
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		fmt.Printf("%-18s%s\n", "Name:", result.Name)
+		fmt.Printf("%-18s%s\n", "Namehash:", result.NameHash)
+		fmt.Printf("%-18s%s\n", "State:", result.State)
+		if result.Wrapped != nil {
+			fmt.Printf("%-18s%s (NameWrapper)\n", "Owner:", result.Owner)
+			fmt.Printf("%-18s%s\n", "Wrapped owner:", result.Wrapped.Owner)
+			fmt.Printf("%-18s%s\n", "Fuses:", fuseDescription(result.Wrapped.Fuses))
+			fmt.Printf("%-18s%s\n", "Fuse expiry:", orNone(result.Wrapped.Expiry))
+		} else {
+			fmt.Printf("%-18s%s\n", "Owner:", orNone(result.Owner))
+		}
+		if registered {
+			fmt.Printf("%-18s%s\n", "Registrant:", orNone(result.Registrant))
+			fmt.Printf("%-18s%s\n", "Expiry:", orNone(result.Expiry))
+		}
+		fmt.Printf("%-18s%s\n", "Resolver:", orNone(result.Resolver))
+		fmt.Printf("%-18s%s\n", "Address:", orNone(result.Address))
+		fmt.Printf("%-18s%s\n", "Primary name:", orNone(result.PrimaryName))
+		for _, key := range profileTextKeys {
+			if value, exists := result.Profile[key]; exists {
+				fmt.Printf("%-18s%s\n", key+":", value)
+			}
+		}
+	},
+}
+
+// registrationState returns the state of a name directly under .eth given
+// the expiry of its registration and the time of the latest block
+func registrationState(expiry time.Time, now time.Time) string {
+	switch {
+	case expiry.IsZero():
+		return "Available"
+	case now.Before(expiry):
+		return "Registered"
+	case now.Before(expiry.Add(gracePeriod)):
+		return "Expired; in grace period"
+	default:
+		return "Available"
+	}
+}
+
+func init() {
+	nameCmd.AddCommand(nameInfoCmd)
+}
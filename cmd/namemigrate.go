@@ -0,0 +1,96 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// nameMigrateCmd represents the name migrate command
+var nameMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate an ENS name from the legacy registry",
+	Long: `Migrate a name from the legacy Ethereum Name Service (ENS) registry to the current registry.  For example:
+
+    ens name migrate --passphrase="my secret passphrase" sub.enstest.eth
+
+Names directly under .eth were migrated when the current registry was deployed, but other names are read from the legacy registry until they are migrated.  Migration copies the name's owner, resolver and TTL to the current registry; as the resolver is unchanged the name's address and other records are preserved.  The name's parent must already be in the current registry, and the migration is carried out by the owner of the parent.
+
+The keystore for the account that owns the parent of the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to migrate the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(currentNetwork != nil && currentNetwork.legacyRegistry != ens.UnknownAddress, exitNotFound, "There is no legacy registry on this network")
+		parts := strings.SplitN(args[0], ".", 2)
+		assert(len(parts) == 2, exitBadInput, "Top-level names cannot be migrated")
+		label, parent := parts[0], parts[1]
+
+		migrated, err := ensClient.RecordExists(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain registry record")
+		assert(!migrated, exitBadInput, "Name is already in the current registry")
+		parentMigrated, err := ensClient.RecordExists(commandCtx, parent)
+		errCheck(err, quiet, "Failed to obtain registry record for parent")
+		assert(parentMigrated, exitBadInput, fmt.Sprintf("Parent %s must be migrated first", parent))
+
+		legacy, err := ensClient.LegacyRecord(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain legacy registry record")
+		assert(legacy.Owner != ens.UnknownAddress, exitNotFound, "Name is not in the legacy registry")
+		if !quiet {
+			fmt.Println("Legacy registry:")
+			printRegistryRecord(legacy)
+		}
+
+		// The migration is carried out by the owner of the parent in the current registry
+		parentOwner, err := ensClient.Owner(commandCtx, parent)
+		errCheck(err, quiet, "Failed to obtain owner of parent")
+		wallet, account, err := obtainWalletAndAccount(parentOwner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the parent")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.SetSubnameRecord(commandCtx, opts, parent, label, legacy)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner":    legacy.Owner.Hex(),
+			"resolver": legacy.Resolver.Hex()}, "Name migrate")
+
+		if !quiet && sendsTransactions() && wait {
+			current, err := ensClient.Record(commandCtx, args[0])
+			errCheck(err, quiet, "Failed to obtain registry record")
+			fmt.Println("Current registry:")
+			printRegistryRecord(current)
+		}
+	},
+}
+
+// printRegistryRecord prints the record of a name in a registry
+func printRegistryRecord(record *ensclient.Record) {
+	fmt.Printf("  %-16s%s\n", "Owner:", record.Owner.Hex())
+	fmt.Printf("  %-16s%s\n", "Resolver:", record.Resolver.Hex())
+	fmt.Printf("  %-16s%d\n", "TTL:", record.TTL)
+}
+
+func init() {
+	nameCmd.AddCommand(nameMigrateCmd)
+
+	addTransactionFlags(nameMigrateCmd, "Passphrase for the account that owns the parent of the name")
+}
@@ -0,0 +1,224 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameRegisterAddressStr string
+var nameRegisterDurationStr string
+
+// registrationCommitment is the information required to complete a registration after committing
+type registrationCommitment struct {
+	Name     string        `json:"name"`
+	Owner    string        `json:"owner"`
+	Duration time.Duration `json:"duration"`
+	Secret   string        `json:"secret"`
+}
+
+// nameRegisterCmd represents the name register command
+var nameRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register an ENS name with the permanent registrar",
+	Long: `Register a name with the Ethereum Name Service (ENS) permanent registrar.  For example:
+
+    ens name register --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --duration=1y --passphrase="my secret passphrase" enstest.eth
+
+Registration is a two-step process.  The first run of this command sends a commitment to register the name and stores the details of the commitment in a temporary file.  Once the commitment is old enough (usually one minute) run the same command again to complete the registration.
+
+The duration can be given in years (e.g. 1y) or days (e.g. 90d) and must be at least 28 days.
+
+The price plus 10% is sent when registering to allow for price fluctuations, with any excess refunded; a different value can be sent with --value.  The balance of the address is checked before sending, unless --skip-balance-check is supplied.
+
+Names with fewer than 5 characters cost considerably more.  For these a warning with the yearly price is shown, and the registration must be confirmed unless --yes is supplied.
+
+The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to commit or register is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(nameRegisterAddressStr != "", exitBadInput, "Address that will own the name is required")
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Only names directly under .eth can be registered")
+		label, err := ens.Domain(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+		duration, err := parseRegistrationDuration(nameRegisterDurationStr)
+		errCheck(badInput(err), quiet, "Invalid duration")
+		assert(duration >= minRegistrationDuration, exitBadInput, "Duration must be at least 28 days")
+
+		// Short names cost far more so make sure that this is intended
+		warning, err := shortNameWarning(label)
+		errCheck(err, quiet, "Failed to obtain price")
+		if warning != "" {
+			cli.Assert(confirmWarning(warning), quiet, "Registration not confirmed")
+		}
+
+		// Fetch the wallet and account for the address
+		owner, err := resolveAddress(nameRegisterAddressStr)
+		errCheck(err, quiet, "Failed to obtain owner address")
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the address")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		commitmentFile := registrationCommitmentFile(args[0])
+		saved, err := loadRegistrationCommitment(commitmentFile)
+		if err != nil {
+			// No existing commitment so start the registration
+			isAvailable, err := ensClient.Available(commandCtx, label)
+			errCheck(err, quiet, "Failed to obtain availability")
+			assert(isAvailable, exitBadInput, "Name is not available")
+
+			base, premium, err := ensClient.RentPrice(commandCtx, label, duration)
+			errCheck(err, quiet, "Failed to obtain price")
+			printPrice(new(big.Int).Add(base, premium))
+
+			var secret [32]byte
+			_, err = rand.Read(secret[:])
+			errCheck(err, quiet, "Failed to generate secret")
+			commitment, err := ensClient.MakeCommitment(commandCtx, label, owner, duration, secret)
+			errCheck(err, quiet, "Failed to create commitment")
+
+			tx, err := ensClient.Commit(commandCtx, opts, commitment)
+			errCheck(err, quiet, "Failed to send transaction")
+			// The commitment is saved before it is sent, so that the secret is
+			// not lost if the command fails once the commitment is on chain.
+			// It is also saved for --output-tx as the transaction is expected
+			// to be sent elsewhere
+			saving := !dryRun && !estimateOnly
+			if saving {
+				err = saveRegistrationCommitment(commitmentFile, &registrationCommitment{
+					Name:     args[0],
+					Owner:    owner.Hex(),
+					Duration: duration,
+					Secret:   hexutil.Encode(secret[:]),
+				})
+				errCheck(err, quiet, "Failed to save commitment; the commitment has not been sent")
+			}
+			err = submitTransaction(tx, log.Fields{"name": args[0],
+				"owner":      owner.Hex(),
+				"commitment": hexutil.Encode(commitment[:])}, "Name commit")
+			if err != nil {
+				if saving {
+					os.Remove(commitmentFile)
+				}
+				reportTransactionError(err)
+			}
+			if !saving {
+				return
+			}
+			if !quiet {
+				fmt.Println("Commitment details saved to", commitmentFile)
+				fmt.Println("Once the commitment transaction has been mined and is at least a minute old run this command again to register the name")
+			}
+			if wait && sendsTransactions() {
+				waitForTransaction(tx)
+			}
+			return
+		}
+
+		// Existing commitment so complete the registration
+		assert(common.HexToAddress(saved.Owner) == owner, exitBadInput, fmt.Sprintf("Commitment was made for owner %s", saved.Owner))
+		duration = saved.Duration
+		secretBytes, err := hexutil.Decode(saved.Secret)
+		errCheck(badInput(err), quiet, "Invalid secret in saved commitment")
+		var secret [32]byte
+		copy(secret[:], secretBytes)
+		commitment, err := ensClient.MakeCommitment(commandCtx, label, owner, duration, secret)
+		errCheck(err, quiet, "Failed to create commitment")
+
+		committed, err := ensClient.CommitmentTime(commandCtx, commitment)
+		errCheck(err, quiet, "Failed to obtain commitment")
+		assert(!committed.IsZero(), exitNotFound, fmt.Sprintf("Commitment not found; it may not yet have been mined.  To start again remove %s", commitmentFile))
+		minAge, maxAge, err := ensClient.CommitmentAges(commandCtx)
+		errCheck(err, quiet, "Failed to obtain commitment ages")
+		now, err := chainTime()
+		errCheck(err, quiet, "Failed to obtain latest block")
+		assert(!now.Before(committed.Add(minAge)), exitBadInput, fmt.Sprintf("Commitment is too recent; try again after %v", committed.Add(minAge)))
+		if now.After(committed.Add(maxAge)) {
+			os.Remove(commitmentFile)
+			cli.Err(quiet, "Commitment has expired; run this command again to start a new registration")
+		}
+
+		base, premium, err := ensClient.RentPrice(commandCtx, label, duration)
+		errCheck(err, quiet, "Failed to obtain price")
+		price := new(big.Int).Add(base, premium)
+		printPrice(price)
+		value, err := paymentValue(price)
+		errCheck(badInput(err), quiet, "Invalid value")
+		opts.Value = value
+		err = checkValueBalance(opts, value)
+		errCheck(err, quiet, "Balance check failed")
+
+		tx, err := ensClient.Register(commandCtx, opts, label, owner, duration, secret)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner":    owner.Hex(),
+			"duration": duration,
+			"value":    value}, "Name register")
+		if sendsTransactions() {
+			os.Remove(commitmentFile)
+		}
+	},
+}
+
+// registrationCommitmentFile returns the path of the file holding the
+// registration commitment for a name.  The file is named by the hash of the
+// name so that the name cannot form a path
+func registrationCommitmentFile(name string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("ens-commitment-%x.json", ens.NameHash(name)))
+}
+
+func loadRegistrationCommitment(path string) (*registrationCommitment, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	commitment := &registrationCommitment{}
+	err = json.Unmarshal(data, commitment)
+	return commitment, err
+}
+
+func saveRegistrationCommitment(path string, commitment *registrationCommitment) error {
+	data, err := json.Marshal(commitment)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func init() {
+	nameCmd.AddCommand(nameRegisterCmd)
+
+	nameRegisterCmd.Flags().StringVarP(&nameRegisterAddressStr, "address", "a", "", "Address that will own the name")
+	nameRegisterCmd.Flags().StringVarP(&nameRegisterDurationStr, "duration", "d", "1y", "Duration of the registration")
+	addTransactionFlags(nameRegisterCmd, "Passphrase for the account that will own the name")
+	addValueFlag(nameRegisterCmd)
+	addFiatFlag(nameRegisterCmd)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameRenewAddressStr string
+var nameRenewDurationStr string
+
+// nameRenewCmd represents the name renew command
+var nameRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew the registration of an ENS name",
+	Long: `Renew the registration of a name with the Ethereum Name Service (ENS) permanent registrar.  For example:
+
+    ens name renew --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --duration=1y --passphrase="my secret passphrase" enstest.eth
+
+Any address can pay to renew a name.  The duration can be given in years (e.g. 1y) or days (e.g. 90d).
+
+Names can be renewed in bulk from a file with one name per line, all by the same duration:
+
+    ens name renew --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --duration=1y --from-file=names.txt --passphrase="my secret passphrase"
+
+The price of each name and the total are shown, and the renewals must be confirmed unless --yes is supplied.  If the network has a bulk renewal contract, given as bulkrenewal in the config file, the names are renewed in a single transaction; otherwise each name is renewed by a separate transaction with consecutive nonces.
+
+The price plus 10% is sent to allow for price fluctuations, with any excess refunded; a different value can be sent with --value.  The balance of the address is checked before sending, unless --skip-balance-check is supplied.
+
+The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to renew the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).  For a file this will return 0 only if all of the names are renewed successfully.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(nameRenewAddressStr != "", exitBadInput, "Address paying for the renewal is required")
+		duration, err := parseRegistrationDuration(nameRenewDurationStr)
+		errCheck(badInput(err), quiet, "Invalid duration")
+		if nameRenewFromFile != "" {
+			assert(outputTxFile == "", exitBadInput, "--output-tx cannot be used with --from-file")
+			nameRenewBatch(duration)
+			return
+		}
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Only names directly under .eth can be renewed")
+		label, err := ens.Domain(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+
+		// Ensure that the name is registered and within its grace period
+		expiry, err := ensClient.Expiry(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain expiry")
+		assert(!expiry.IsZero(), exitNotFound, "Name is not registered")
+		assert(time.Now().Before(expiry.Add(gracePeriod)), exitNotFound, "Name has expired beyond its grace period and must be registered again")
+
+		base, premium, err := ensClient.RentPrice(commandCtx, label, duration)
+		errCheck(err, quiet, "Failed to obtain price")
+		price := new(big.Int).Add(base, premium)
+		printPrice(price)
+
+		// Fetch the wallet and account for the address
+		address, err := resolveAddress(nameRenewAddressStr)
+		errCheck(err, quiet, "Failed to obtain renewal address")
+		wallet, account, err := obtainWalletAndAccount(address, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the address")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		opts.Value, err = paymentValue(price)
+		errCheck(badInput(err), quiet, "Invalid value")
+		err = checkValueBalance(opts, opts.Value)
+		errCheck(err, quiet, "Balance check failed")
+
+		tx, err := ensClient.Renew(commandCtx, opts, label, duration)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"duration": duration,
+			"value":    opts.Value}, "Name renew")
+		if !quiet && sendsTransactions() {
+			fmt.Println("New expiry is", expiry.Add(duration))
+		}
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameRenewCmd)
+
+	nameRenewCmd.Flags().StringVarP(&nameRenewAddressStr, "address", "a", "", "Address paying for the renewal")
+	nameRenewCmd.Flags().StringVarP(&nameRenewDurationStr, "duration", "d", "1y", "Duration by which to extend the registration")
+	addTransactionFlags(nameRenewCmd, "Passphrase for the account paying for the renewal")
+	nameRenewCmd.Flags().StringVar(&nameRenewFromFile, "from-file", "", "File of names to renew, one per line")
+	addValueFlag(nameRenewCmd)
+	addFiatFlag(nameRenewCmd)
+}
@@ -0,0 +1,230 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+)
+
+var nameRenewFromFile string
+
+// nameRenewRow is a name to renew in a batch
+type nameRenewRow struct {
+	name   string
+	label  string
+	expiry time.Time
+	price  *big.Int
+}
+
+type nameRenewBatchResult struct {
+	Name          string `json:"name"`
+	Price         string `json:"price"`
+	TransactionID string `json:"transactionid,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// readNameRenewRows reads the names to renew from a file with one name per
+// line, checking that each can be renewed and obtaining its price
+func readNameRenewRows(path string, duration time.Duration) ([]*nameRenewRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows := make([]*nameRenewRow, 0)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		name, err := cleanName(scanner.Text())
+		if err == nil {
+			name, err = normalizeName(expandName(name))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if ens.DomainLevel(name) != 1 {
+			return nil, fmt.Errorf("line %d: only names directly under .eth can be renewed", line)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		row := &nameRenewRow{name: name}
+		if row.label, err = ens.Domain(name); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if row.expiry, err = ensClient.Expiry(commandCtx, name); err != nil {
+			return nil, fmt.Errorf("line %d: failed to obtain expiry: %v", line, err)
+		}
+		if row.expiry.IsZero() {
+			return nil, fmt.Errorf("line %d: %s is not registered", line, name)
+		}
+		if !time.Now().Before(row.expiry.Add(gracePeriod)) {
+			return nil, fmt.Errorf("line %d: %s has expired beyond its grace period and must be registered again", line, name)
+		}
+		base, premium, err := ensClient.RentPrice(commandCtx, row.label, duration)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to obtain price: %v", line, err)
+		}
+		row.price = new(big.Int).Add(base, premium)
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// nameRenewBatch renews the names listed in a file by the same duration,
+// through the bulk renewal contract if the network has one
+func nameRenewBatch(duration time.Duration) {
+	rows, err := readNameRenewRows(nameRenewFromFile, duration)
+	errCheck(err, quiet, "Failed to read names")
+	assert(len(rows) > 0, exitBadInput, "No names to renew")
+	bulk := currentNetwork != nil && currentNetwork.bulkRenewal != ens.UnknownAddress
+	assert(bulk || paymentValueStr == "", exitBadInput, "--value cannot be used with --from-file unless the network has a bulk renewal contract")
+
+	total := new(big.Int)
+	for _, row := range rows {
+		total.Add(total, row.price)
+		if !quiet && !jsonOutput {
+			fmt.Printf("%s: %s (expires %s)\n", row.name, etherutils.WeiToString(row.price, true), row.expiry.UTC().Format(time.RFC1123))
+		}
+	}
+	if !quiet {
+		fmt.Printf("Total for %d names:\n", len(rows))
+	}
+	printPrice(total)
+
+	address, err := resolveAddress(nameRenewAddressStr)
+	errCheck(err, quiet, "Failed to obtain renewal address")
+	wallet, account, err := obtainWalletAndAccount(address, passphrase)
+	errCheck(err, quiet, "Failed to obtain an account for the address")
+	gasPrice, err := gasPriceFlag()
+	errCheck(badInput(err), quiet, "Invalid gas price")
+	opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+	// The account must be able to pay for all of the renewals, not just each one
+	value := new(big.Int)
+	if bulk {
+		value, err = paymentValue(total)
+		errCheck(badInput(err), quiet, "Invalid value")
+	} else {
+		for _, row := range rows {
+			rowValue, err := paymentValue(row.price)
+			errCheck(badInput(err), quiet, "Invalid value")
+			value.Add(value, rowValue)
+		}
+	}
+	err = checkValueBalance(opts, value)
+	errCheck(err, quiet, "Balance check failed")
+	cli.Assert(confirmBatch(fmt.Sprintf("Renew %d names by %v for a total of %s", len(rows), duration, etherutils.WeiToString(total, true))), quiet, "Transactions not confirmed")
+
+	var results []*nameRenewBatchResult
+	if bulk {
+		results = renewBulk(opts, rows, duration, total)
+	} else {
+		results = make([]*nameRenewBatchResult, 0, len(rows))
+		for _, row := range rows {
+			results = append(results, renewSingle(opts, row, duration))
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		if quiet {
+			continue
+		}
+		if jsonOutput {
+			outputJSON(result)
+		} else if result.Error != "" {
+			fmt.Printf("%s: failed: %s\n", result.Name, result.Error)
+		} else if result.TransactionID != "" {
+			fmt.Printf("%s: renewed by transaction %s\n", result.Name, result.TransactionID)
+		}
+	}
+	if !quiet && !jsonOutput {
+		fmt.Printf("Renewed %d of %d names\n", len(results)-failed, len(results))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// renewBulk renews the names in a single transaction through the bulk renewal contract
+func renewBulk(opts *bind.TransactOpts, rows []*nameRenewRow, duration time.Duration, total *big.Int) []*nameRenewBatchResult {
+	labels := make([]string, 0, len(rows))
+	results := make([]*nameRenewBatchResult, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row.label)
+		results = append(results, &nameRenewBatchResult{Name: row.name, Price: row.price.String()})
+	}
+	var tx *types.Transaction
+	var err error
+	opts.Value, err = paymentValue(total)
+	if err == nil {
+		tx, err = ensClient.RenewAll(commandCtx, opts, labels, duration)
+	}
+	if err == nil {
+		err = submitChainedTransaction(opts, tx, log.Fields{"names": len(labels),
+			"duration": duration,
+			"value":    opts.Value}, "Names renew")
+	}
+	for _, result := range results {
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.TransactionID = tx.Hash().Hex()
+		}
+	}
+	return results
+}
+
+// renewSingle renews a single name of a batch
+func renewSingle(opts *bind.TransactOpts, row *nameRenewRow, duration time.Duration) *nameRenewBatchResult {
+	result := &nameRenewBatchResult{Name: row.name, Price: row.price.String()}
+	var tx *types.Transaction
+	var err error
+	opts.Value, err = paymentValue(row.price)
+	if err == nil {
+		tx, err = ensClient.Renew(commandCtx, opts, row.label, duration)
+	}
+	if err == nil {
+		err = submitChainedTransaction(opts, tx, log.Fields{"name": row.name,
+			"duration": duration,
+			"value":    opts.Value}, "Name renew")
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.TransactionID = tx.Hash().Hex()
+	}
+	return result
+}
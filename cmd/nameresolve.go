@@ -0,0 +1,131 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var nameResolveConcurrency int
+
+type nameResolveResult struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// nameResolveCmd represents the name resolve command
+var nameResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Resolve a list of ENS names read from stdin",
+	Long: `Resolve the addresses of names registered with the Ethereum Name Service (ENS), reading one name per line from stdin and writing the name and its address separated by a tab.  For example:
+
+    ens name resolve --concurrency=20 <names.txt
+
+Duplicate names are resolved once.  Names that fail to resolve are reported on stderr and do not stop the remaining names being resolved.  Results are written in the order in which the names were read.
+
+In quiet mode this will return 0 if all of the names resolve, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(nameResolveConcurrency > 0, exitBadInput, "Concurrency must be at least 1")
+
+		names := make([]string, 0)
+		failed := false
+		seen := make(map[string]bool)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			name, err := cleanName(scanner.Text())
+			if err != nil {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", strings.TrimSpace(scanner.Text()), err)
+				}
+				failed = true
+				continue
+			}
+			name = expandName(name)
+			if !common.IsHexAddress(name) {
+				normalized, err := normalizeName(name)
+				if err == nil {
+					name = normalized
+				}
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+		errCheck(scanner.Err(), quiet, "Failed to read names")
+
+		// Resolve with a bounded number of workers, keeping the results in order
+		results := make([]chan *nameResolveResult, len(names))
+		for i := range results {
+			results[i] = make(chan *nameResolveResult, 1)
+		}
+		workers := make(chan struct{}, nameResolveConcurrency)
+		go func() {
+			for i, name := range names {
+				workers <- struct{}{}
+				go func(i int, name string) {
+					defer func() { <-workers }()
+					result := &nameResolveResult{Name: name}
+					if address, err := resolveAddress(name); err != nil {
+						result.Error = err.Error()
+					} else {
+						result.Address = address.Hex()
+					}
+					results[i] <- result
+				}(i, name)
+			}
+		}()
+
+		for i := range results {
+			result := <-results[i]
+			if result.Error != "" {
+				failed = true
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", result.Name, result.Error)
+				}
+			}
+			if quiet {
+				continue
+			}
+			if jsonOutput {
+				outputJSON(result)
+			} else if result.Error == "" {
+				fmt.Printf("%s\t%s\n", result.Name, result.Address)
+			}
+		}
+		if quiet {
+			if failed {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameResolveCmd)
+
+	nameResolveCmd.Flags().IntVar(&nameResolveConcurrency, "concurrency", 10, "Number of names to resolve at the same time")
+}
@@ -15,11 +15,8 @@ package cmd
 
 import (
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -35,42 +32,43 @@ var nameSetCmd = &cobra.Command{
 
     ens name set --name=enstest.eth --passphrase="my secret passphrase" 0xED96dD3Be847b387217EF9DE5B20D8392A6cdf40
 
+The name can also be supplied as the second argument:
+
+    ens name set --passphrase="my secret passphrase" 0xED96dD3Be847b387217EF9DE5B20D8392A6cdf40 enstest.eth
+
 The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to set the name is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to set the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(nameSetName != "", quiet, "Name is required")
+		if nameSetName == "" && len(args) > 1 {
+			nameSetName = args[1]
+		}
+		assert(nameSetName != "", exitBadInput, "Name is required")
+		assert(common.IsHexAddress(args[0]), exitBadInput, "Invalid address")
 
 		// Obtain the reverse registrar contract
-		reverseRegistrar, err := ens.ReverseRegistrarContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain reverse registrar contract")
+		reverseRegistrar, err := reverseRegistrarContract()
+		errCheck(err, quiet, "Failed to obtain reverse registrar contract")
 
 		address := common.HexToAddress(args[0])
 
 		// Fetch the wallet and account for the address
 		wallet, account, err := obtainWalletAndAccount(address, passphrase)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain account details for the address %s", args[0]))
+		errCheck(err, quiet, fmt.Sprintf("Failed to obtain account details for the address %s", args[0]))
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid gas price %s", gasPriceStr))
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, fmt.Sprintf("Invalid gas price %s", gasPriceStr))
 
 		session := ens.CreateReverseRegistrarSession(chainID, &wallet, account, passphrase, reverseRegistrar, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		configureTransactOpts(&session.TransactOpts)
 
 		// Clean up the name prior to setting
 		nameSetName = ens.Normalize(nameSetName)
 
 		tx, err := ens.SetName(session, nameSetName)
-		cli.ErrCheck(err, quiet, "Failed to set name for that address")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"address":   args[0],
-			"name":      nameSetName}).Info("Name set")
+		errCheck(err, quiet, "Failed to set name for that address")
+		handleTransaction(tx, log.Fields{"address": args[0],
+			"name": nameSetName}, "Name set")
 	},
 }
 
@@ -0,0 +1,87 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+type nameSignResult struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// nameSignCmd represents the name sign command
+var nameSignCmd = &cobra.Command{
+	Use:   "sign <name> <message>",
+	Short: "Sign a message as the address of an ENS name",
+	Long: `Sign a message with the account of the address of a name registered with the Ethereum Name Service (ENS), proving control of the name.  For example:
+
+    ens name sign --passphrase="my secret passphrase" enstest.eth "Log in to example.com"
+
+The message is signed as an EIP-191 personal message, as used by wallets and "log in with Ethereum" services, and the 65-byte signature is printed as hex.  The signature can be checked with 'ens name verify'.
+
+The keystore for the address of the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the message is signed successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 2, exitBadInput, "A name and a message are required")
+		address, err := resolveAddress(args[0])
+		errCheck(err, quiet, "Failed to obtain address")
+
+		wallet, account, err := obtainWalletAndAccount(address, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the address of the name")
+		var signature []byte
+		if ledger {
+			signature, err = wallet.SignText(*account, []byte(args[1]))
+		} else {
+			signature, err = wallet.SignTextWithPassphrase(*account, passphrase, []byte(args[1]))
+		}
+		errCheck(err, quiet, "Failed to sign message")
+		// Signatures are published with the recovery ID offset by 27
+		if signature[64] < 27 {
+			signature[64] += 27
+		}
+		if quiet {
+			return
+		}
+
+		if jsonOutput {
+			outputJSON(&nameSignResult{
+				Name:      args[0],
+				Address:   address.Hex(),
+				Message:   args[1],
+				Signature: hexutil.Encode(signature),
+			})
+			return
+		}
+		fmt.Println(hexutil.Encode(signature))
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameSignCmd)
+
+	nameSignCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase for the account of the address of the name")
+	nameSignCmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File containing the passphrase")
+	nameSignCmd.Flags().BoolVar(&passphraseStdin, "passphrase-stdin", false, "Read the passphrase from the first line of stdin")
+	nameSignCmd.Flags().BoolVar(&ledger, "ledger", false, "Sign the message with a Ledger hardware wallet rather than a local keystore")
+	nameSignCmd.Flags().StringVar(&clefEndpoint, "clef", "", "Sign the message with the clef instance at the given IPC path or URL rather than a local keystore")
+	nameSignCmd.Flags().StringVar(&hdPath, "hd-path", "m/44'/60'/0'/0/0", "HD derivation path of the account on the Ledger")
+}
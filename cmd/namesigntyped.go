@@ -0,0 +1,169 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/spf13/cobra"
+)
+
+var nameSignTypedTypesFile string
+var nameSignTypedDataFile string
+
+type nameSignTypedResult struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	PrimaryType string `json:"primarytype"`
+	StructHash  string `json:"structhash"`
+	Hash        string `json:"hash"`
+	Signature   string `json:"signature"`
+}
+
+// nameSignTypedCmd represents the name sign-typed command
+var nameSignTypedCmd = &cobra.Command{
+	Use:   "sign-typed <name>",
+	Short: "Sign EIP-712 typed data as the address of an ENS name",
+	Long: `Sign EIP-712 typed data with the account of the address of a name registered with the Ethereum Name Service (ENS), as required by offchain services such as delegation registries.  For example:
+
+    ens name sign-typed --types=types.json --data=delegation.json --passphrase="my secret passphrase" enstest.eth
+
+The types file holds the definitions of the types, including EIP712Domain, for example:
+
+    {"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+     "Delegation": [{"name": "delegate", "type": "address"}, {"name": "nonce", "type": "uint256"}]}
+
+The data file holds the primary type, domain and message, for example:
+
+    {"primaryType": "Delegation", "domain": {"name": "Example", "chainId": "1"},
+     "message": {"delegate": "0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1", "nonce": "1"}}
+
+The types and data are checked against each other before anything is signed.  The struct hash of the message, the hash that is signed and the 65-byte signature are printed as hex.
+
+The keystore for the address of the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the data is signed successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 1, exitBadInput, "A name is required")
+		assert(nameSignTypedTypesFile != "", exitBadInput, "--types is required")
+		assert(nameSignTypedDataFile != "", exitBadInput, "--data is required")
+
+		typedData, err := readTypedData(nameSignTypedTypesFile, nameSignTypedDataFile)
+		errCheck(badInput(err), quiet, "Invalid typed data")
+		structHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+		errCheck(badInput(err), quiet, "Invalid typed data")
+		hash, rawData, err := apitypes.TypedDataAndHash(*typedData)
+		errCheck(badInput(err), quiet, "Invalid typed data")
+
+		address, err := resolveAddress(args[0])
+		errCheck(err, quiet, "Failed to obtain address")
+		wallet, account, err := obtainWalletAndAccount(address, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the address of the name")
+		var signature []byte
+		switch {
+		case clefEndpoint != "":
+			// Clef shows the typed data for approval, so is sent the data rather than its hash
+			var typedJSON []byte
+			typedJSON, err = json.Marshal(typedData)
+			errCheck(err, quiet, "Failed to encode typed data")
+			signature, err = wallet.SignData(*account, accounts.MimetypeTypedData, typedJSON)
+		case ledger:
+			signature, err = wallet.SignData(*account, accounts.MimetypeTypedData, []byte(rawData))
+		default:
+			signature, err = wallet.SignDataWithPassphrase(*account, passphrase, accounts.MimetypeTypedData, []byte(rawData))
+		}
+		errCheck(err, quiet, "Failed to sign typed data")
+		// Signatures are published with the recovery ID offset by 27
+		if signature[64] < 27 {
+			signature[64] += 27
+		}
+		if quiet {
+			return
+		}
+
+		result := &nameSignTypedResult{
+			Name:        args[0],
+			Address:     address.Hex(),
+			PrimaryType: typedData.PrimaryType,
+			StructHash:  structHash.String(),
+			Hash:        hexutil.Encode(hash),
+			Signature:   hexutil.Encode(signature),
+		}
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		fmt.Printf("%-18s%s\n", "Struct hash:", result.StructHash)
+		fmt.Printf("%-18s%s\n", "Hash:", result.Hash)
+		fmt.Printf("%-18s%s\n", "Signature:", result.Signature)
+	},
+}
+
+// readTypedData reads EIP-712 typed data from a file of types and a file
+// holding the primary type, domain and message, checking that they match
+func readTypedData(typesFile string, dataFile string) (*apitypes.TypedData, error) {
+	typesJSON, err := ioutil.ReadFile(typesFile)
+	if err != nil {
+		return nil, err
+	}
+	dataJSON, err := ioutil.ReadFile(dataFile)
+	if err != nil {
+		return nil, err
+	}
+	typedData := &apitypes.TypedData{}
+	if err = json.Unmarshal(dataJSON, typedData); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", dataFile, err)
+	}
+	if err = json.Unmarshal(typesJSON, &typedData.Types); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", typesFile, err)
+	}
+	if _, exists := typedData.Types["EIP712Domain"]; !exists {
+		return nil, fmt.Errorf("types do not include EIP712Domain")
+	}
+	if typedData.PrimaryType == "" {
+		return nil, fmt.Errorf("data does not include a primary type")
+	}
+	if typedData.PrimaryType == "EIP712Domain" {
+		return nil, fmt.Errorf("primary type cannot be EIP712Domain")
+	}
+	if _, exists := typedData.Types[typedData.PrimaryType]; !exists {
+		return nil, fmt.Errorf("types do not include the primary type %s", typedData.PrimaryType)
+	}
+	// Encoding checks the types, and that the domain and message conform to them
+	if _, err = typedData.HashStruct("EIP712Domain", typedData.Domain.Map()); err != nil {
+		return nil, fmt.Errorf("invalid domain: %v", err)
+	}
+	if _, err = typedData.HashStruct(typedData.PrimaryType, typedData.Message); err != nil {
+		return nil, fmt.Errorf("invalid message: %v", err)
+	}
+	return typedData, nil
+}
+
+func init() {
+	nameCmd.AddCommand(nameSignTypedCmd)
+
+	nameSignTypedCmd.Flags().StringVar(&nameSignTypedTypesFile, "types", "", "JSON file containing the EIP-712 type definitions")
+	nameSignTypedCmd.Flags().StringVar(&nameSignTypedDataFile, "data", "", "JSON file containing the primary type, domain and message to sign")
+	nameSignTypedCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase for the account of the address of the name")
+	nameSignTypedCmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File containing the passphrase")
+	nameSignTypedCmd.Flags().BoolVar(&passphraseStdin, "passphrase-stdin", false, "Read the passphrase from the first line of stdin")
+	nameSignTypedCmd.Flags().BoolVar(&ledger, "ledger", false, "Sign the data with a Ledger hardware wallet rather than a local keystore")
+	nameSignTypedCmd.Flags().StringVar(&clefEndpoint, "clef", "", "Sign the data with the clef instance at the given IPC path or URL rather than a local keystore")
+	nameSignTypedCmd.Flags().StringVar(&hdPath, "hd-path", "m/44'/60'/0'/0/0", "HD derivation path of the account on the Ledger")
+}
@@ -0,0 +1,113 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadTypedData(t *testing.T) {
+	types := `{
+  "EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+  "Mail": [{"name": "to", "type": "address"}, {"name": "contents", "type": "string"}]
+}`
+	tests := []struct {
+		name  string
+		types string
+		data  string
+		err   bool
+	}{
+		{
+			name:  "Good",
+			types: types,
+			data:  `{"primaryType": "Mail", "domain": {"name": "Mail", "chainId": "1"}, "message": {"to": "0x5FfC014343cd971B7eb70732021E26C35B744cc4", "contents": "Hello"}}`,
+		},
+		{
+			name:  "InvalidTypes",
+			types: `{"EIP712Domain": [`,
+			data:  `{"primaryType": "Mail", "domain": {"name": "Mail", "chainId": "1"}, "message": {}}`,
+			err:   true,
+		},
+		{
+			name:  "InvalidData",
+			types: types,
+			data:  `{"primaryType": `,
+			err:   true,
+		},
+		{
+			name:  "NoDomainType",
+			types: `{"Mail": [{"name": "contents", "type": "string"}]}`,
+			data:  `{"primaryType": "Mail", "domain": {"name": "Mail"}, "message": {"contents": "Hello"}}`,
+			err:   true,
+		},
+		{
+			name:  "NoPrimaryType",
+			types: types,
+			data:  `{"domain": {"name": "Mail", "chainId": "1"}, "message": {"contents": "Hello"}}`,
+			err:   true,
+		},
+		{
+			name:  "PrimaryTypeDomain",
+			types: types,
+			data:  `{"primaryType": "EIP712Domain", "domain": {"name": "Mail", "chainId": "1"}, "message": {"name": "Mail", "chainId": "1"}}`,
+			err:   true,
+		},
+		{
+			name:  "UnknownPrimaryType",
+			types: types,
+			data:  `{"primaryType": "Letter", "domain": {"name": "Mail", "chainId": "1"}, "message": {"contents": "Hello"}}`,
+			err:   true,
+		},
+		{
+			name:  "MessageMismatch",
+			types: types,
+			data:  `{"primaryType": "Mail", "domain": {"name": "Mail", "chainId": "1"}, "message": {"to": "not an address", "contents": "Hello"}}`,
+			err:   true,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "ens-typed-data")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typesFile := filepath.Join(dir, test.name+"-types.json")
+			dataFile := filepath.Join(dir, test.name+"-data.json")
+			if err := ioutil.WriteFile(typesFile, []byte(test.types), 0600); err != nil {
+				t.Fatalf("failed to write types: %v", err)
+			}
+			if err := ioutil.WriteFile(dataFile, []byte(test.data), 0600); err != nil {
+				t.Fatalf("failed to write data: %v", err)
+			}
+			typedData, err := readTypedData(typesFile, dataFile)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error, obtained %v", typedData)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if typedData.PrimaryType != "Mail" {
+				t.Errorf("primary type is %s, expected Mail", typedData.PrimaryType)
+			}
+		})
+	}
+}
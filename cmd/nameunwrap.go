@@ -0,0 +1,73 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameUnwrapOwnerStr string
+
+// nameUnwrapCmd represents the name unwrap command
+var nameUnwrapCmd = &cobra.Command{
+	Use:   "unwrap",
+	Short: "Unwrap an ENS name from the NameWrapper",
+	Long: `Unwrap a name registered with the Ethereum Name Service (ENS) from the NameWrapper, returning it to the registry.  For example:
+
+    ens name unwrap --passphrase="my secret passphrase" enstest.eth
+
+The name is unwrapped by the owner of the wrapped name, and is given to them unless --owner is supplied.  For a name directly under .eth the new owner also becomes the registrant.  Names with the CANNOT_UNWRAP fuse burned cannot be unwrapped.
+
+The keystore for the account that owns the wrapped name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to unwrap the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ensClient.NameWrapper() != ens.UnknownAddress, exitNotFound, "There is no NameWrapper on this network")
+		data, err := ensClient.WrappedData(commandCtx, args[0])
+		if err == ensclient.ErrNotWrapped {
+			fail(exitNotFound, "Name is not wrapped")
+		}
+		errCheck(err, quiet, "Failed to obtain wrapped name")
+		assert(data.Fuses&ensclient.CannotUnwrap == 0, exitBadInput, "Name cannot be unwrapped as its CANNOT_UNWRAP fuse is burned")
+		assert(data.Owner != ens.UnknownAddress, exitNotFound, "Wrapped name has no owner; it might have expired")
+
+		owner := data.Owner
+		if nameUnwrapOwnerStr != "" {
+			owner, err = resolveAddress(nameUnwrapOwnerStr)
+			errCheck(err, quiet, "Failed to obtain owner of the unwrapped name")
+		}
+
+		wallet, account, err := obtainWalletAndAccount(data.Owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the wrapped name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.Unwrap(commandCtx, opts, args[0], owner)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner": owner.Hex()}, "Name unwrap")
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameUnwrapCmd)
+
+	nameUnwrapCmd.Flags().StringVarP(&nameUnwrapOwnerStr, "owner", "o", "", "Owner of the unwrapped name (defaults to the owner of the wrapped name)")
+	addTransactionFlags(nameUnwrapCmd, "Passphrase for the account that owns the wrapped name")
+}
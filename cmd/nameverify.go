@@ -0,0 +1,97 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+type nameVerifyResult struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Signer  string `json:"signer"`
+	Valid   bool   `json:"valid"`
+}
+
+// nameVerifyCmd represents the name verify command
+var nameVerifyCmd = &cobra.Command{
+	Use:   "verify <name> <message> <signature>",
+	Short: "Verify a message signed as the address of an ENS name",
+	Long: `Verify that a message was signed by the account of the address of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens name verify enstest.eth "Log in to example.com" 0x<65 bytes of hex>
+
+The signature is of an EIP-191 personal message, as created by 'ens name sign' and by wallets.  It is valid if it was made by the address to which the name currently resolves.
+
+In quiet mode this will return 0 if the signature is valid, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 3, exitBadInput, "A name, a message and a signature are required")
+		signature, err := hexutil.Decode(args[2])
+		errCheck(err, quiet, "Signature must be 0x-prefixed hex")
+		assert(len(signature) == 65, exitBadInput, "Signature must be 65 bytes")
+		address, err := resolveAddress(args[0])
+		errCheck(err, quiet, "Failed to obtain address")
+
+		signer, err := personalSigner([]byte(args[1]), signature)
+		errCheck(badInput(err), quiet, "Invalid signature")
+		valid := signer == address
+		if quiet {
+			if valid {
+				os.Exit(0)
+			}
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			outputJSON(&nameVerifyResult{
+				Name:    args[0],
+				Address: address.Hex(),
+				Signer:  signer.Hex(),
+				Valid:   valid,
+			})
+		} else if valid {
+			fmt.Printf("Signature is valid for %s (%s)\n", args[0], address.Hex())
+		} else {
+			fmt.Printf("Signature is not valid for %s: it was signed by %s but the name resolves to %s\n", args[0], signer.Hex(), address.Hex())
+		}
+		if !valid {
+			os.Exit(1)
+		}
+	},
+}
+
+// personalSigner recovers the address that signed an EIP-191 personal message
+func personalSigner(message []byte, signature []byte) (common.Address, error) {
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pubkey, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+func init() {
+	nameCmd.AddCommand(nameVerifyCmd)
+}
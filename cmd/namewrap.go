@@ -0,0 +1,96 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var nameWrapOwnerStr string
+
+// nameWrapCmd represents the name wrap command
+var nameWrapCmd = &cobra.Command{
+	Use:   "wrap",
+	Short: "Wrap an ENS name with the NameWrapper",
+	Long: `Wrap a name registered with the Ethereum Name Service (ENS) with the NameWrapper, which then holds the name in the registry and issues a token for it to the owner.  For example:
+
+    ens name wrap --passphrase="my secret passphrase" enstest.eth
+
+A name directly under .eth is wrapped by its registrant.  Any other name is wrapped by its owner in the registry, who must first allow the NameWrapper to manage their names; if this has not been done then a transaction to do so is sent before the name is wrapped.  The wrapped name is owned by the account that wraps it unless --owner is supplied, and keeps its current resolver.
+
+The keystore for the account that wraps the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to wrap the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ensClient.NameWrapper() != ens.UnknownAddress, exitNotFound, "There is no NameWrapper on this network")
+		wrapped, err := ensClient.IsWrapped(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain owner")
+		assert(!wrapped, exitBadInput, "Name is already wrapped")
+
+		// Names directly under .eth are wrapped by the registrant, others by the registry owner
+		var sender common.Address
+		if ens.DomainLevel(args[0]) == 1 {
+			sender, err = ensClient.Registrant(commandCtx, args[0])
+			errCheck(err, quiet, "Failed to obtain registrant; the name might not be registered or might have expired")
+		} else {
+			sender, err = ensClient.Owner(commandCtx, args[0])
+			errCheck(err, quiet, "Failed to obtain owner")
+			assert(sender != ens.UnknownAddress, exitNotFound, "Name has no owner")
+		}
+		owner := sender
+		if nameWrapOwnerStr != "" {
+			owner, err = resolveAddress(nameWrapOwnerStr)
+			errCheck(err, quiet, "Failed to obtain owner of the wrapped name")
+		}
+		resolver, err := ensClient.Resolver(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain resolver")
+
+		wallet, account, err := obtainWalletAndAccount(sender, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		if ens.DomainLevel(args[0]) != 1 {
+			approved, err := ensClient.NameWrapperApproved(commandCtx, sender)
+			errCheck(err, quiet, "Failed to obtain NameWrapper approval")
+			if !approved {
+				tx, err := ensClient.ApproveNameWrapper(commandCtx, opts)
+				errCheck(err, quiet, "Failed to send transaction")
+				handleTransaction(tx, log.Fields{"owner": sender.Hex(),
+					"namewrapper": ensClient.NameWrapper().Hex()}, "NameWrapper approval")
+				opts.Nonce = new(big.Int).SetUint64(tx.Nonce() + 1)
+			}
+		}
+
+		tx, err := ensClient.Wrap(commandCtx, opts, args[0], owner, resolver)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner":    owner.Hex(),
+			"resolver": resolver.Hex()}, "Name wrap")
+	},
+}
+
+func init() {
+	nameCmd.AddCommand(nameWrapCmd)
+
+	nameWrapCmd.Flags().StringVarP(&nameWrapOwnerStr, "owner", "o", "", "Owner of the wrapped name (defaults to the account that wraps it)")
+	addTransactionFlags(nameWrapCmd, "Passphrase for the account that wraps the name")
+}
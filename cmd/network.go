@@ -0,0 +1,154 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/orinocopay/go-etherutils/ens/reverseregistrarcontract"
+)
+
+// network holds the ENS contract addresses for a network
+type network struct {
+	name          string
+	chainID       uint64
+	registry      common.Address
+	baseRegistrar common.Address
+	controller    common.Address
+	nameWrapper   common.Address
+	// publicResolver and reverseRegistrar are found through the registry
+	// unless they are set
+	publicResolver   common.Address
+	reverseRegistrar common.Address
+	// l2Gateways are the hosts of gateways that answer offchain lookups
+	// from the state of an L2, keyed to the name of the L2
+	l2Gateways map[string]string
+	// bulkRenewal is the contract that renews several names at once, if any
+	bulkRenewal common.Address
+	// subgraph is the URL of the ENS subgraph for the network, if any
+	subgraph string
+	// legacyRegistry is the registry in use before the 2020 migration, if any
+	legacyRegistry common.Address
+	// auctionMinNameLength is the minimum length of names that can be
+	// auctioned by the auction registrar, if it differs from the default.
+	// The registrar does not expose its minimum so this can only be set in
+	// the config file
+	auctionMinNameLength int
+}
+
+// defaultAuctionMinNameLength is the minimum length of names that can be
+// auctioned by the original auction registrar; shorter names can be
+// invalidated by anyone
+const defaultAuctionMinNameLength = 7
+
+// networks are the known networks, keyed by name
+var networks = map[string]*network{
+	"mainnet": {
+		name:           "mainnet",
+		chainID:        1,
+		registry:       common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+		baseRegistrar:  common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85"),
+		controller:     common.HexToAddress("0x253553366Da8546fC250F225fe3d25d0C782303b"),
+		nameWrapper:    common.HexToAddress("0xD4416b13d2b3a9aBae7AcD5D6C2BbDBE25686401"),
+		legacyRegistry: common.HexToAddress("0x314159265dD8dbb310642f98f50C066173C1259b"),
+	},
+	"goerli": {
+		name:          "goerli",
+		chainID:       5,
+		registry:      common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+		baseRegistrar: common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85"),
+		controller:    common.HexToAddress("0xCc5e7dB10E65EED1BBD105359e7268aa660f6734"),
+		nameWrapper:   common.HexToAddress("0x114D4603199df73e7D157787f8778E21fCd13066"),
+	},
+	"sepolia": {
+		name:          "sepolia",
+		chainID:       11155111,
+		registry:      common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+		baseRegistrar: common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85"),
+		controller:    common.HexToAddress("0xFED6a969AaA60E4961FCD3EBF1A2e8913ac65B72"),
+		nameWrapper:   common.HexToAddress("0x0635513f179D50A207757E05759CbD106d7dFcE8"),
+	},
+}
+
+var networkName string
+
+// currentNetwork is the network to which the tool is connected, or nil if it is not known
+var currentNetwork *network
+
+// networkByName returns the network with the given name
+func networkByName(name string) (*network, error) {
+	selected, exists := networks[strings.ToLower(name)]
+	if !exists {
+		names := make([]string, 0, len(networks))
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown network %s; known networks are %s", name, strings.Join(names, ", "))
+	}
+	return selected, nil
+}
+
+// publicResolverAddress returns the address of the public resolver for the
+// current network, which is the address of resolver.eth unless it is set
+func publicResolverAddress() (common.Address, error) {
+	if currentNetwork != nil && currentNetwork.publicResolver != ens.UnknownAddress {
+		return currentNetwork.publicResolver, nil
+	}
+	address, err := ensClient.Address(commandCtx, "resolver.eth")
+	if err == nil && address == ens.UnknownAddress {
+		err = errors.New("resolver.eth has no address")
+	}
+	return address, err
+}
+
+// reverseRegistrarContract obtains the reverse registrar for the current
+// network, which is the owner of addr.reverse unless it is set
+func reverseRegistrarContract() (*reverseregistrarcontract.ReverseRegistrarContract, error) {
+	if currentNetwork != nil && currentNetwork.reverseRegistrar != ens.UnknownAddress {
+		return reverseregistrarcontract.NewReverseRegistrarContract(currentNetwork.reverseRegistrar, client)
+	}
+	address, err := registryContract.Owner(callOpts(), ens.NameHash("addr.reverse"))
+	if err != nil {
+		return nil, err
+	}
+	if address == ens.UnknownAddress {
+		return nil, errors.New("addr.reverse has no owner")
+	}
+	return reverseregistrarcontract.NewReverseRegistrarContract(address, client)
+}
+
+// auctionMinNameLength returns the minimum length of names that can be
+// auctioned on the current network
+func auctionMinNameLength() int {
+	if currentNetwork != nil && currentNetwork.auctionMinNameLength != 0 {
+		return currentNetwork.auctionMinNameLength
+	}
+	return defaultAuctionMinNameLength
+}
+
+// networkByChainID returns the network with the given chain ID, or nil if it is not known
+func networkByChainID(chainID uint64) *network {
+	for _, network := range networks {
+		if network.chainID == chainID {
+			return network
+		}
+	}
+	return nil
+}
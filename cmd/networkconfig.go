@@ -0,0 +1,119 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// networkConfig is the configuration of a network in the config file.  All
+// fields are optional for known networks, where they override the built-in
+// addresses; other networks need at least a chain ID and a registry
+type networkConfig struct {
+	ChainID          uint64 `mapstructure:"chainid"`
+	Registry         string `mapstructure:"registry"`
+	BaseRegistrar    string `mapstructure:"baseregistrar"`
+	Controller       string `mapstructure:"controller"`
+	NameWrapper      string `mapstructure:"namewrapper"`
+	PublicResolver   string `mapstructure:"publicresolver"`
+	ReverseRegistrar string `mapstructure:"reverseregistrar"`
+	BulkRenewal      string `mapstructure:"bulkrenewal"`
+	// L2Gateways maps the hosts of gateways that answer from L2 state to the
+	// names of the L2s
+	L2Gateways map[string]string `mapstructure:"l2gateways"`
+	// Subgraph is the URL of the ENS subgraph for the network
+	Subgraph string `mapstructure:"subgraph"`
+	// AuctionMinNameLength is the minimum length of names that can be
+	// auctioned, for auction registrars that differ from the original
+	AuctionMinNameLength int `mapstructure:"auctionminnamelength"`
+}
+
+// loadNetworkConfig merges the networks in the config file over the known
+// networks.  For example, in YAML, where addresses are quoted so that they
+// are not read as numbers:
+//
+//	networks:
+//	  mainnet:
+//	    controller: "0x253553366Da8546fC250F225fe3d25d0C782303b"
+//	    l2gateways:
+//	      gateway.example.com: base
+//	    subgraph: https://subgraph.example.com/ens
+//	  devnet:
+//	    chainid: 1337
+//	    registry: "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+func loadNetworkConfig() error {
+	configs := make(map[string]*networkConfig)
+	if err := viper.UnmarshalKey("networks", &configs); err != nil {
+		return err
+	}
+	for name, config := range configs {
+		name = strings.ToLower(name)
+		selected, exists := networks[name]
+		if !exists {
+			if config.ChainID == 0 || config.Registry == "" {
+				return fmt.Errorf("network %s needs a chain ID and a registry", name)
+			}
+			selected = &network{name: name}
+		}
+		if config.ChainID != 0 {
+			if exists && config.ChainID != selected.chainID {
+				return fmt.Errorf("network %s is chain %d not %d", name, selected.chainID, config.ChainID)
+			}
+			selected.chainID = config.ChainID
+		}
+		addresses := []struct {
+			field   string
+			value   string
+			address *common.Address
+		}{
+			{"registry", config.Registry, &selected.registry},
+			{"baseregistrar", config.BaseRegistrar, &selected.baseRegistrar},
+			{"controller", config.Controller, &selected.controller},
+			{"namewrapper", config.NameWrapper, &selected.nameWrapper},
+			{"publicresolver", config.PublicResolver, &selected.publicResolver},
+			{"reverseregistrar", config.ReverseRegistrar, &selected.reverseRegistrar},
+			{"bulkrenewal", config.BulkRenewal, &selected.bulkRenewal},
+		}
+		for _, address := range addresses {
+			if address.value == "" {
+				continue
+			}
+			if !common.IsHexAddress(address.value) {
+				return fmt.Errorf("invalid %s address %s for network %s", address.field, address.value, name)
+			}
+			*address.address = common.HexToAddress(address.value)
+		}
+		for host, l2 := range config.L2Gateways {
+			if selected.l2Gateways == nil {
+				selected.l2Gateways = make(map[string]string)
+			}
+			selected.l2Gateways[strings.ToLower(host)] = l2
+		}
+		if config.Subgraph != "" {
+			selected.subgraph = config.Subgraph
+		}
+		if config.AuctionMinNameLength < 0 {
+			return fmt.Errorf("invalid auction minimum name length %d for network %s", config.AuctionMinNameLength, name)
+		}
+		if config.AuctionMinNameLength != 0 {
+			selected.auctionMinNameLength = config.AuctionMinNameLength
+		}
+		networks[name] = selected
+	}
+	return nil
+}
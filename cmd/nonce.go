@@ -18,11 +18,14 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/orinocopay/go-etherutils/cli"
-	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
 
+type nonceResult struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
+
 // nonceCmd represents the nonce command
 var nonceCmd = &cobra.Command{
 	Use:   "nonce",
@@ -31,20 +34,27 @@ var nonceCmd = &cobra.Command{
 
     ens nonce 0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the nonce can be obtained, otherwise 1.`,
+In quiet mode this will return 0 if the nonce can be obtained, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
 
-		nonceAddress, err := ens.Resolve(client, args[0])
-		cli.ErrCheck(err, quiet, "Failed to obtain nonce address")
+		nonceAddress, err := resolveAddress(args[0])
+		errCheck(err, quiet, "Failed to obtain nonce address")
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(commandCtx, 5*time.Second)
 		defer cancel()
 
 		nonce, err := client.PendingNonceAt(ctx, nonceAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain nonce")
+		errCheck(err, quiet, "Failed to obtain nonce")
 
 		if !quiet {
-			fmt.Println(nonce)
+			if jsonOutput {
+				outputJSON(&nonceResult{
+					Address: nonceAddress.Hex(),
+					Nonce:   nonce,
+				})
+			} else {
+				fmt.Println(nonce)
+			}
 		}
 	},
 }
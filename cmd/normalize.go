@@ -0,0 +1,96 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// namePolicy applies UTS-46 non-transitional processing, which ENS used
+// before ENSIP-15.  ENSIP-15 builds on this with its own handling of emoji
+// and checks for confusable characters and mixed scripts, which are not
+// applied here
+var namePolicy = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false))
+
+var noNormalize bool
+
+// nameSeparators maps the ideographic and fullwidth full stops, which UTS-46
+// treats as label separators and which are often pasted in place of one, to
+// an ASCII full stop
+var nameSeparators = strings.NewReplacer("\u3002", ".", "\uff0e", ".", "\uff61", ".")
+
+// cleanName tidies a name as typed or pasted before it is expanded or
+// normalized, trimming surrounding whitespace, a trailing full stop and,
+// unless --no-normalize is supplied, converting other full stops to ASCII.
+// Names with whitespace, control characters or empty labels are rejected
+func cleanName(name string) (string, error) {
+	cleaned := strings.TrimSpace(name)
+	if !noNormalize {
+		cleaned = nameSeparators.Replace(cleaned)
+	}
+	cleaned = strings.TrimSuffix(cleaned, ".")
+	if cleaned == "" {
+		return "", errors.New("name is empty")
+	}
+	for _, c := range cleaned {
+		if unicode.IsSpace(c) || unicode.IsControl(c) {
+			return "", fmt.Errorf("invalid name %q: disallowed character %q", name, c)
+		}
+	}
+	for _, label := range strings.Split(cleaned, ".") {
+		if label == "" {
+			return "", fmt.Errorf("invalid name %q: empty label", name)
+		}
+	}
+	return cleaned, nil
+}
+
+// normalizeName normalizes a name unless --no-normalize is supplied, in
+// which case the name is used exactly as given
+func normalizeName(name string) (string, error) {
+	if noNormalize {
+		return name, nil
+	}
+	return uts46Normalize(name)
+}
+
+// uts46Normalize normalizes a name with UTS-46, lowercasing and
+// NFC-normalizing it and rejecting disallowed characters
+func uts46Normalize(name string) (string, error) {
+	normalized, err := namePolicy.ToUnicode(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid name %s: %v", name, err)
+	}
+	// UTS-46 without STD3 rules allows all printable ASCII; ENS only allows
+	// letters, digits, hyphens and underscores
+	for _, c := range normalized {
+		if c < 0x80 && !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' && c != '.' {
+			return "", fmt.Errorf("invalid name %s: disallowed character %q", name, c)
+		}
+	}
+	return normalized, nil
+}
+
+// warnNoNormalize warns that names are not being normalized
+func warnNoNormalize() {
+	if noNormalize && !quiet {
+		fmt.Fprintln(os.Stderr, "Warning: names are not normalized (--no-normalize); a name that is not normalized hashes differently from its normalized form, so other ENS clients will not find it and anything sent to it could be lost")
+	}
+}
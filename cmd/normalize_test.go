@@ -0,0 +1,64 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCleanName(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		noNormalize bool
+		cleaned     string
+		err         bool
+	}{
+		{name: "Plain", input: "enstest.eth", cleaned: "enstest.eth"},
+		{name: "Whitespace", input: "  enstest.eth\n", cleaned: "enstest.eth"},
+		{name: "TrailingStop", input: "enstest.eth.", cleaned: "enstest.eth"},
+		{name: "IdeographicStop", input: "enstest。eth", cleaned: "enstest.eth"},
+		{name: "FullwidthStop", input: "enstest．eth", cleaned: "enstest.eth"},
+		{name: "HalfwidthStop", input: "enstest｡eth", cleaned: "enstest.eth"},
+		{name: "NoNormalizeStop", input: "enstest。eth", noNormalize: true, cleaned: "enstest。eth"},
+		{name: "Label", input: "enstest", cleaned: "enstest"},
+		{name: "Empty", input: "", err: true},
+		{name: "OnlyWhitespace", input: " \t ", err: true},
+		{name: "OnlyStop", input: ".", err: true},
+		{name: "InnerSpace", input: "ens test.eth", err: true},
+		{name: "Control", input: "enstest\x00.eth", err: true},
+		{name: "EmptyLabel", input: "enstest..eth", err: true},
+		{name: "LeadingStop", input: ".enstest.eth", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			noNormalize = test.noNormalize
+			defer func() { noNormalize = false }()
+			cleaned, err := cleanName(test.input)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error, obtained %q", cleaned)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cleaned != test.cleaned {
+				t.Errorf("cleaned name is %q, expected %q", cleaned, test.cleaned)
+			}
+		})
+	}
+}
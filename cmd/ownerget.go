@@ -0,0 +1,148 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type ownerGetResult struct {
+	Name       string           `json:"name"`
+	NameHash   string           `json:"namehash"`
+	Owner      string           `json:"owner"`
+	Registrant string           `json:"registrant,omitempty"`
+	Resolver   string           `json:"resolver,omitempty"`
+	Address    string           `json:"address,omitempty"`
+	Wrapped    *ownerGetWrapped `json:"wrapped,omitempty"`
+}
+
+type ownerGetWrapped struct {
+	Owner     string   `json:"owner"`
+	Fuses     uint32   `json:"fuses"`
+	FuseNames []string `json:"fusenames"`
+	Expiry    string   `json:"expiry,omitempty"`
+}
+
+// ownerGetCmd represents the owner get command
+var ownerGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain the owner of an ENS name",
+	Long: `Obtain the owners, resolver and address of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens owner get enstest.eth
+
+The registry owner controls the name's records and subdomains.  For names directly under .eth the registrant, who can transfer the name and reclaim the registry ownership, is also shown.  If the name is wrapped its registry owner is the NameWrapper, and the owner of the wrapped name, its burned fuses and the expiry of its fuses are also shown.
+
+The owners of a name at an earlier block can be obtained with --block, giving a block number or one of latest, safe, finalized or earliest.  For example:
+
+    ens owner get --block=finalized enstest.eth
+
+In quiet mode this will return 0 if the name has an owner, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+		if quiet {
+			return
+		}
+
+		result := &ownerGetResult{
+			Name:     args[0],
+			NameHash: nameHashHex(args[0]),
+			Owner:    owner.Hex(),
+		}
+		if ens.DomainLevel(args[0]) == 1 {
+			// The registrant is unavailable if the name has expired
+			if registrantAddress, err := ensClient.Registrant(commandCtx, args[0]); err == nil {
+				result.Registrant = registrantAddress.Hex()
+			}
+		}
+		if owner == ensClient.NameWrapper() {
+			result.Wrapped, err = wrappedInfo(args[0])
+			errCheck(err, quiet, "Cannot obtain wrapped name")
+		}
+		if resolverAddress, err := ensClient.Resolver(commandCtx, args[0]); err == nil && resolverAddress != ens.UnknownAddress {
+			result.Resolver = resolverAddress.Hex()
+			if address, err := ensClient.Address(commandCtx, args[0]); err == nil && address != ens.UnknownAddress {
+				result.Address = address.Hex()
+			}
+		}
+
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		if result.Wrapped != nil {
+			fmt.Printf("%-18s%s (NameWrapper)\n", "Owner:", result.Owner)
+			fmt.Printf("%-18s%s\n", "Wrapped owner:", result.Wrapped.Owner)
+			fmt.Printf("%-18s%s\n", "Fuses:", fuseDescription(result.Wrapped.Fuses))
+			fmt.Printf("%-18s%s\n", "Fuse expiry:", orNone(result.Wrapped.Expiry))
+		} else {
+			fmt.Printf("%-18s%s\n", "Owner:", result.Owner)
+		}
+		if ens.DomainLevel(args[0]) == 1 {
+			fmt.Printf("%-18s%s\n", "Registrant:", orNone(result.Registrant))
+		}
+		fmt.Printf("%-18s%s\n", "Resolver:", orNone(result.Resolver))
+		fmt.Printf("%-18s%s\n", "Address:", orNone(result.Address))
+	},
+}
+
+// wrappedInfo obtains the owner, fuses and fuse expiry of a wrapped name
+func wrappedInfo(name string) (*ownerGetWrapped, error) {
+	data, err := ensClient.WrappedData(commandCtx, name)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &ownerGetWrapped{
+		Owner:     data.Owner.Hex(),
+		Fuses:     data.Fuses,
+		FuseNames: ensclient.FuseNames(data.Fuses),
+	}
+	if data.Expiry.Unix() != 0 {
+		wrapped.Expiry = data.Expiry.UTC().Format(time.RFC3339)
+	}
+	return wrapped, nil
+}
+
+// orNone returns the value, or "none" if it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// fuseDescription returns the fuses of a wrapped name in hex along with
+// the names of those that are burned
+func fuseDescription(fuses uint32) string {
+	names := ensclient.FuseNames(fuses)
+	if len(names) == 0 {
+		return fmt.Sprintf("0x%x (none burned)", fuses)
+	}
+	return fmt.Sprintf("0x%x (%s)", fuses, strings.Join(names, ", "))
+}
+
+func init() {
+	ownerCmd.AddCommand(ownerGetCmd)
+
+	addBlockFlag(ownerGetCmd)
+}
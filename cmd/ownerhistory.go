@@ -0,0 +1,174 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+const registryEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":true,"name":"label","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"NewOwner","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"owner","type":"address"}],"name":"Transfer","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"node","type":"bytes32"},{"indexed":false,"name":"resolver","type":"address"}],"name":"NewResolver","type":"event"}
+]`
+
+// baseRegistrarEventsABI is the ABI of the ERC-721 transfer event of the base registrar
+const baseRegistrarEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"}
+]`
+
+var ownerHistoryFromBlock uint64
+
+type ownerHistoryResult struct {
+	Block         uint64 `json:"block"`
+	Timestamp     string `json:"timestamp"`
+	TransactionID string `json:"transactionid"`
+	Event         string `json:"event"`
+	Owner         string `json:"owner,omitempty"`
+	Registrant    string `json:"registrant,omitempty"`
+	Resolver      string `json:"resolver,omitempty"`
+}
+
+// ownerHistoryCmd represents the owner history command
+var ownerHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the history of ownership of an ENS name",
+	Long: `Show the changes of owner and resolver of a name registered with the Ethereum Name Service (ENS), oldest first.  For example:
+
+    ens owner history --from-block=9380380 enstest.eth
+
+Changes of registry owner and resolver are shown, along with changes of registrant for names directly under .eth.  Events are read from --from-block onwards; some nodes limit the number of blocks that can be searched, in which case a later block is required.
+
+In quiet mode this will return 0 if the name has any history, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(currentNetwork != nil, exitNotFound, fmt.Sprintf("No registry known for chain %v", chainID))
+		registryEvents, err := abi.JSON(strings.NewReader(registryEventsABI))
+		errCheck(err, quiet, "Failed to parse registry events")
+		baseRegistrarEvents, err := abi.JSON(strings.NewReader(baseRegistrarEventsABI))
+		errCheck(err, quiet, "Failed to parse registrar events")
+
+		node := common.Hash(ens.NameHash(args[0]))
+		fromBlock := new(big.Int).SetUint64(ownerHistoryFromBlock)
+		queries := []ethereum.FilterQuery{{
+			FromBlock: fromBlock,
+			Addresses: []common.Address{currentNetwork.registry},
+			Topics:    [][]common.Hash{{registryEvents.Events["Transfer"].ID, registryEvents.Events["NewResolver"].ID}, {node}},
+		}}
+		if parts := strings.SplitN(args[0], ".", 2); len(parts) == 2 {
+			queries = append(queries, ethereum.FilterQuery{
+				FromBlock: fromBlock,
+				Addresses: []common.Address{currentNetwork.registry},
+				Topics:    [][]common.Hash{{registryEvents.Events["NewOwner"].ID}, {common.Hash(ens.NameHash(parts[1]))}, {common.Hash(ens.LabelHash(parts[0]))}},
+			})
+		}
+		if ens.DomainLevel(args[0]) == 1 {
+			id, err := ensclient.LabelID(args[0])
+			errCheck(badInput(err), quiet, "Invalid name")
+			queries = append(queries, ethereum.FilterQuery{
+				FromBlock: fromBlock,
+				Addresses: []common.Address{currentNetwork.baseRegistrar},
+				Topics:    [][]common.Hash{{baseRegistrarEvents.Events["Transfer"].ID}, nil, nil, {common.BigToHash(id)}},
+			})
+		}
+
+		var logs []types.Log
+		for _, query := range queries {
+			ctx, cancel := context.WithTimeout(commandCtx, 60*time.Second)
+			queryLogs, err := client.FilterLogs(ctx, query)
+			cancel()
+			errCheck(err, quiet, "Failed to obtain events")
+			logs = append(logs, queryLogs...)
+		}
+		sort.Slice(logs, func(i, j int) bool {
+			if logs[i].BlockNumber != logs[j].BlockNumber {
+				return logs[i].BlockNumber < logs[j].BlockNumber
+			}
+			return logs[i].Index < logs[j].Index
+		})
+		assert(len(logs) > 0, exitNotFound, "No history for that name")
+		if quiet {
+			return
+		}
+
+		timestamps := make(map[uint64]time.Time)
+		for _, log := range logs {
+			result := &ownerHistoryResult{
+				Block:         log.BlockNumber,
+				TransactionID: log.TxHash.Hex(),
+			}
+			if _, exists := timestamps[log.BlockNumber]; !exists {
+				ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+				header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+				cancel()
+				errCheck(err, quiet, "Failed to obtain block")
+				timestamps[log.BlockNumber] = time.Unix(int64(header.Time), 0)
+			}
+			result.Timestamp = timestamps[log.BlockNumber].UTC().Format(time.RFC3339)
+
+			if log.Address == currentNetwork.baseRegistrar {
+				result.Event = "Registrant"
+				result.Registrant = common.BytesToAddress(log.Topics[2].Bytes()).Hex()
+			} else {
+				event, err := registryEvents.EventByID(log.Topics[0])
+				if err != nil {
+					continue
+				}
+				values := make(map[string]interface{})
+				if err = registryEvents.UnpackIntoMap(values, event.Name, log.Data); err != nil {
+					continue
+				}
+				if owner, exists := values["owner"]; exists {
+					result.Event = "Owner"
+					result.Owner = owner.(common.Address).Hex()
+				}
+				if resolver, exists := values["resolver"]; exists {
+					result.Event = "Resolver"
+					result.Resolver = resolver.(common.Address).Hex()
+				}
+			}
+
+			if jsonOutput {
+				outputJSON(result)
+				continue
+			}
+			switch result.Event {
+			case "Owner":
+				fmt.Printf("%s (block %d): owner set to %s\n", result.Timestamp, result.Block, result.Owner)
+			case "Resolver":
+				fmt.Printf("%s (block %d): resolver set to %s\n", result.Timestamp, result.Block, result.Resolver)
+			case "Registrant":
+				fmt.Printf("%s (block %d): registrant set to %s\n", result.Timestamp, result.Block, result.Registrant)
+			}
+		}
+	},
+}
+
+func init() {
+	ownerCmd.AddCommand(ownerHistoryCmd)
+
+	ownerHistoryCmd.Flags().Uint64Var(&ownerHistoryFromBlock, "from-block", 0, "Block from which to search for events")
+}
@@ -0,0 +1,443 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+// controllerEventsABI is the ABI of the registration events of the current
+// and earlier .eth controllers, which carry the label of the name.  The
+// earlier event is named NameRegistered0 when parsed
+const controllerEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":false,"name":"name","type":"string"},{"indexed":true,"name":"label","type":"bytes32"},{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"baseCost","type":"uint256"},{"indexed":false,"name":"premium","type":"uint256"},{"indexed":false,"name":"expires","type":"uint256"}],"name":"NameRegistered","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":false,"name":"name","type":"string"},{"indexed":true,"name":"label","type":"bytes32"},{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"cost","type":"uint256"},{"indexed":false,"name":"expires","type":"uint256"}],"name":"NameRegistered","type":"event"}
+]`
+
+// nameWrapperEventsABI is the ABI of the ERC-1155 transfer event of the
+// name wrapper
+const nameWrapperEventsABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"operator","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"id","type":"uint256"},{"indexed":false,"name":"value","type":"uint256"}],"name":"TransferSingle","type":"event"}
+]`
+
+// subgraphPageSize is the number of entities requested from the subgraph at a time
+const subgraphPageSize = 1000
+
+var ownerListNamesSubgraph string
+var ownerListNamesFromBlock uint64
+var ownerListNamesLimit int
+var ownerListNamesOffset int
+
+type ownerListNamesResult struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// ownerListNamesCmd represents the owner list-names command
+var ownerListNamesCmd = &cobra.Command{
+	Use:   "list-names",
+	Short: "List the ENS names controlled by an address",
+	Long: `List the names registered with the Ethereum Name Service (ENS) that are controlled by an address, along with how the address controls each name: as its registrant, its owner in the registry or the owner of the wrapped name.  For example:
+
+    ens owner list-names --limit=20 --offset=40 0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1
+
+Names are listed in order, with --offset and --limit selecting a page of the list.
+
+Names are obtained from the ENS subgraph given by --subgraph or the network's subgraph in the config file.  Without a subgraph names are found by scanning events from --from-block onwards and checking that the address still controls each name.  Scanning is slow, and some nodes limit the number of blocks that can be searched, in which case a later block is required.  Labels that cannot be found from the events are shown by their hash, for example [0x4f5b...].eth.
+
+In quiet mode this will return 0 if the address controls any names, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(args) == 1, exitBadInput, "An address is required")
+		assert(ownerListNamesLimit >= 0, exitBadInput, "Limit cannot be negative")
+		assert(ownerListNamesOffset >= 0, exitBadInput, "Offset cannot be negative")
+		address, err := resolveAddress(args[0])
+		errCheck(badInput(err), quiet, "Invalid address")
+
+		subgraph := ownerListNamesSubgraph
+		if subgraph == "" && currentNetwork != nil {
+			subgraph = currentNetwork.subgraph
+		}
+		var roles map[string][]string
+		if subgraph != "" {
+			roles, err = subgraphOwnedNames(subgraph, address)
+			errCheck(err, quiet, "Failed to obtain names from the subgraph")
+		} else {
+			assert(currentNetwork != nil, exitNotFound, fmt.Sprintf("No registry known for chain %v", chainID))
+			roles, err = eventOwnedNames(address)
+			errCheck(err, quiet, "Failed to obtain names from events")
+		}
+		assert(len(roles) > 0, exitNotFound, "No names found for that address")
+
+		names := make([]string, 0, len(roles))
+		for name := range roles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if ownerListNamesOffset >= len(names) {
+			names = names[:0]
+		} else {
+			names = names[ownerListNamesOffset:]
+		}
+		if ownerListNamesLimit > 0 && len(names) > ownerListNamesLimit {
+			names = names[:ownerListNamesLimit]
+		}
+		if quiet {
+			return
+		}
+
+		for _, name := range names {
+			if jsonOutput {
+				outputJSON(&ownerListNamesResult{Name: name, Roles: roles[name]})
+				continue
+			}
+			fmt.Printf("%s\t%s\n", name, strings.Join(roles[name], ", "))
+		}
+	},
+}
+
+// addRole adds a role to a name, keeping the roles in the order in which they are found
+func addRole(roles map[string][]string, name string, role string) {
+	for _, existing := range roles[name] {
+		if existing == role {
+			return
+		}
+	}
+	roles[name] = append(roles[name], role)
+}
+
+// subgraphOwnedNames obtains the names controlled by an address from the
+// ENS subgraph, keyed to the roles through which the address controls them.
+// The subgraph keeps the registrant of a registration once it has expired,
+// so only registrations that expire after the latest block are included
+func subgraphOwnedNames(subgraph string, address common.Address) (map[string][]string, error) {
+	now, err := chainTime()
+	if err != nil {
+		return nil, err
+	}
+	queries := []struct {
+		role  string
+		query string
+	}{
+		{"registrant", `query($owner: String!, $last: String!, $now: BigInt!) { items: registrations(first: %d, orderBy: id, where: {registrant: $owner, expiryDate_gt: $now, id_gt: $last}) { id domain { name } } }`},
+		{"owner", `query($owner: String!, $last: String!) { items: domains(first: %d, orderBy: id, where: {owner: $owner, id_gt: $last}) { id name } }`},
+		{"wrapped owner", `query($owner: String!, $last: String!) { items: wrappedDomains(first: %d, orderBy: id, where: {owner: $owner, id_gt: $last}) { id domain { name } } }`},
+	}
+	roles := make(map[string][]string)
+	for _, query := range queries {
+		// Pages follow on from the last entity of the previous page, as the
+		// subgraph limits how many entities can be skipped
+		last := ""
+		for {
+			var response struct {
+				Data struct {
+					Items []struct {
+						ID     string `json:"id"`
+						Name   string `json:"name"`
+						Domain struct {
+							Name string `json:"name"`
+						} `json:"domain"`
+					} `json:"items"`
+				} `json:"data"`
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			variables := map[string]interface{}{"owner": strings.ToLower(address.Hex()), "last": last}
+			if query.role == "registrant" {
+				variables["now"] = fmt.Sprintf("%d", now.Unix())
+			}
+			if err := subgraphQuery(subgraph, fmt.Sprintf(query.query, subgraphPageSize), variables, &response); err != nil {
+				return nil, err
+			}
+			if len(response.Errors) > 0 {
+				return nil, fmt.Errorf("subgraph error: %s", response.Errors[0].Message)
+			}
+			for _, item := range response.Data.Items {
+				name := item.Name
+				if name == "" {
+					name = item.Domain.Name
+				}
+				if name != "" {
+					addRole(roles, name, query.role)
+				}
+			}
+			if len(response.Data.Items) < subgraphPageSize {
+				break
+			}
+			last = response.Data.Items[len(response.Data.Items)-1].ID
+		}
+	}
+	return roles, nil
+}
+
+// subgraphQuery sends a GraphQL query to a subgraph and decodes the response
+func subgraphQuery(subgraph string, query string, variables map[string]interface{}, response interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(commandCtx, 60*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subgraph, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("subgraph returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// registryParent is the parent and label hash of a node in the registry
+type registryParent struct {
+	node  common.Hash
+	label common.Hash
+}
+
+// eventOwnedNames obtains the names controlled by an address by scanning
+// the events of the registry, base registrar and name wrapper, keyed to the
+// roles through which the address controls them
+func eventOwnedNames(address common.Address) (map[string][]string, error) {
+	registryEvents, err := abi.JSON(strings.NewReader(registryEventsABI))
+	if err != nil {
+		return nil, err
+	}
+	baseRegistrarEvents, err := abi.JSON(strings.NewReader(baseRegistrarEventsABI))
+	if err != nil {
+		return nil, err
+	}
+	fromBlock := new(big.Int).SetUint64(ownerListNamesFromBlock)
+	addressTopic := common.BytesToHash(address.Bytes())
+	ethNode := common.Hash(ens.NameHash("eth"))
+
+	// Registrations directly under .eth, which are tokens of the base registrar
+	registrarLogs, err := ownerListNamesLogs(ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []common.Address{currentNetwork.baseRegistrar},
+		Topics:    [][]common.Hash{{baseRegistrarEvents.Events["Transfer"].ID}, nil, {addressTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[common.Hash]registryParent)
+	registrations := make(map[common.Hash]bool)
+	for _, log := range registrarLogs {
+		label := log.Topics[3]
+		if registrations[label] {
+			continue
+		}
+		// This fails for expired names, which are no longer controlled
+		if registrant, err := ensClient.TokenRegistrant(commandCtx, label.Big()); err != nil || registrant != address {
+			continue
+		}
+		registrations[label] = true
+		parents[crypto.Keccak256Hash(ethNode.Bytes(), label.Bytes())] = registryParent{node: ethNode, label: label}
+	}
+
+	// Names in the registry, where the owner is not indexed so all new
+	// owners and transfers are scanned
+	registryLogs, err := ownerListNamesLogs(ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []common.Address{currentNetwork.registry},
+		Topics:    [][]common.Hash{{registryEvents.Events["NewOwner"].ID, registryEvents.Events["Transfer"].ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates := make(map[common.Hash]bool)
+	for _, log := range registryLogs {
+		values := make(map[string]interface{})
+		event, err := registryEvents.EventByID(log.Topics[0])
+		if err != nil || registryEvents.UnpackIntoMap(values, event.Name, log.Data) != nil {
+			continue
+		}
+		node := log.Topics[1]
+		if event.Name == "NewOwner" {
+			node = crypto.Keccak256Hash(log.Topics[1].Bytes(), log.Topics[2].Bytes())
+			parents[node] = registryParent{node: log.Topics[1], label: log.Topics[2]}
+		}
+		if owner, ok := values["owner"].(common.Address); ok && owner == address {
+			candidates[node] = true
+		}
+	}
+	owners := make(map[common.Hash]bool)
+	for node := range candidates {
+		owner, err := registryContract.Owner(callOpts(), node)
+		if err == nil && owner == address {
+			owners[node] = true
+		}
+	}
+
+	// Wrapped names, whose names are held by the name wrapper
+	names := make(map[common.Hash]string)
+	wrapped := make(map[common.Hash]bool)
+	if currentNetwork.nameWrapper != ens.UnknownAddress {
+		nameWrapperEvents, err := abi.JSON(strings.NewReader(nameWrapperEventsABI))
+		if err != nil {
+			return nil, err
+		}
+		wrapperLogs, err := ownerListNamesLogs(ethereum.FilterQuery{
+			FromBlock: fromBlock,
+			Addresses: []common.Address{currentNetwork.nameWrapper},
+			Topics:    [][]common.Hash{{nameWrapperEvents.Events["TransferSingle"].ID}, nil, nil, {addressTopic}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range wrapperLogs {
+			values, err := nameWrapperEvents.Events["TransferSingle"].Inputs.NonIndexed().Unpack(log.Data)
+			if err != nil || len(values) == 0 {
+				continue
+			}
+			id, ok := values[0].(*big.Int)
+			if !ok {
+				continue
+			}
+			node := common.BigToHash(id)
+			if owner, err := ensClient.WrappedTokenOwner(commandCtx, id); err != nil || owner != address {
+				continue
+			}
+			wrapped[node] = true
+			if name, err := ensClient.WrappedTokenName(commandCtx, id); err == nil {
+				names[node] = name
+			}
+		}
+	}
+
+	labels, err := registeredLabels(parents)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string][]string)
+	for label := range registrations {
+		addRole(roles, nodeName(crypto.Keccak256Hash(ethNode.Bytes(), label.Bytes()), parents, labels, names), "registrant")
+	}
+	for node := range owners {
+		addRole(roles, nodeName(node, parents, labels, names), "owner")
+	}
+	for node := range wrapped {
+		addRole(roles, nodeName(node, parents, labels, names), "wrapped owner")
+	}
+	return roles, nil
+}
+
+// registeredLabels obtains the labels of names directly under .eth from
+// the registration events of the .eth controllers, keyed to their hashes
+func registeredLabels(parents map[common.Hash]registryParent) (map[common.Hash]string, error) {
+	controllerEvents, err := abi.JSON(strings.NewReader(controllerEventsABI))
+	if err != nil {
+		return nil, err
+	}
+	labels := map[common.Hash]string{common.Hash(ens.LabelHash("eth")): "eth"}
+	ethNode := common.Hash(ens.NameHash("eth"))
+	hashes := make([]common.Hash, 0)
+	for _, parent := range parents {
+		if parent.node == ethNode {
+			hashes = append(hashes, parent.label)
+		}
+	}
+	// Controllers have changed over time, so events are accepted from any
+	// contract but only if the label matches its hash
+	eventIDs := []common.Hash{controllerEvents.Events["NameRegistered"].ID, controllerEvents.Events["NameRegistered0"].ID}
+	for start := 0; start < len(hashes); start += 100 {
+		end := start + 100
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		logs, err := ownerListNamesLogs(ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(ownerListNamesFromBlock),
+			Topics:    [][]common.Hash{eventIDs, hashes[start:end]},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			event, err := controllerEvents.EventByID(log.Topics[0])
+			if err != nil {
+				continue
+			}
+			values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+			if err != nil || len(values) == 0 {
+				continue
+			}
+			if label, ok := values[0].(string); ok && common.Hash(ens.LabelHash(label)) == log.Topics[1] {
+				labels[log.Topics[1]] = label
+			}
+		}
+	}
+	return labels, nil
+}
+
+// nodeName builds the name of a node from its parents, showing labels
+// that are not known by their hash
+func nodeName(node common.Hash, parents map[common.Hash]registryParent, labels map[common.Hash]string, names map[common.Hash]string) string {
+	if name, exists := names[node]; exists {
+		return name
+	}
+	parent, exists := parents[node]
+	if !exists {
+		if node == common.Hash(ens.NameHash("eth")) {
+			return "eth"
+		}
+		return fmt.Sprintf("[%s]", node.Hex())
+	}
+	label, exists := labels[parent.label]
+	if !exists {
+		label = fmt.Sprintf("[%s]", parent.label.Hex())
+	}
+	name := label
+	if parent.node != (common.Hash{}) {
+		name = fmt.Sprintf("%s.%s", label, nodeName(parent.node, parents, labels, names))
+	}
+	names[node] = name
+	return name
+}
+
+// ownerListNamesLogs obtains the logs that match a query
+func ownerListNamesLogs(query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, cancel := context.WithTimeout(commandCtx, 60*time.Second)
+	defer cancel()
+	return client.FilterLogs(ctx, query)
+}
+
+func init() {
+	ownerCmd.AddCommand(ownerListNamesCmd)
+
+	ownerListNamesCmd.Flags().StringVar(&ownerListNamesSubgraph, "subgraph", "", "URL of the ENS subgraph from which to obtain names")
+	ownerListNamesCmd.Flags().Uint64Var(&ownerListNamesFromBlock, "from-block", 0, "Block from which to search for events when there is no subgraph")
+	ownerListNamesCmd.Flags().IntVar(&ownerListNamesLimit, "limit", 100, "Maximum number of names to list, or 0 for all")
+	ownerListNamesCmd.Flags().IntVar(&ownerListNamesOffset, "offset", 0, "Number of names to skip before listing")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var ownerSetRegistrantToStr string
+
+// ownerSetRegistrantCmd represents the owner set-registrant command
+var ownerSetRegistrantCmd = &cobra.Command{
+	Use:   "set-registrant",
+	Short: "Transfer the registration of a .eth name",
+	Long: `Transfer the registration of a .eth name registered with the Ethereum Name Service (ENS) permanent registrar to another address.  For example:
+
+    ens owner set-registrant --to=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" enstest.eth
+
+The registrant and the registry owner of a .eth name are distinct.  The registrant holds the name's token in the base registrar, can renew it and can reclaim registry ownership at any time.  The registry owner controls the name's records (resolver, subdomains etc.) and is transferred with 'ens owner transfer'.  This command transfers the registrant only and leaves the registry owner unchanged, so the new registrant should reclaim registry ownership if it is also required.
+
+The keystore for the account that is the current registrant must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to transfer the registration is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ownerSetRegistrantToStr != "", exitBadInput, "Address to which to transfer the registration of the name is required")
+		assert(ens.DomainLevel(args[0]) == 1, exitBadInput, "Only names directly under .eth have registrants")
+
+		newRegistrant, err := resolveAddress(ownerSetRegistrantToStr)
+		errCheck(err, quiet, "Failed to obtain new registrant address")
+
+		// Fetch the current registrant of the name
+		currentRegistrant, err := ensClient.Registrant(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain registrant; the name might not be registered or might have expired")
+		assert(bytes.Compare(currentRegistrant.Bytes(), newRegistrant.Bytes()) != 0, exitBadInput, "Name is already registered to that address")
+
+		// Fetch the wallet and account for the registrant
+		wallet, account, err := obtainWalletAndAccount(currentRegistrant, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the registrant of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		tx, err := ensClient.SetRegistrant(commandCtx, opts, args[0], newRegistrant)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"registrant": newRegistrant.Hex()}, "Registrant transfer")
+	},
+}
+
+func init() {
+	ownerCmd.AddCommand(ownerSetRegistrantCmd)
+
+	ownerSetRegistrantCmd.Flags().StringVarP(&ownerSetRegistrantToStr, "to", "t", "", "Address to which to transfer the registration of the name")
+	addTransactionFlags(ownerSetRegistrantCmd, "Passphrase for the account that is the registrant of the name")
+}
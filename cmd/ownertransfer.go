@@ -0,0 +1,98 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var ownerTransferToStr string
+var ownerTransferDeed bool
+
+// ownerTransferCmd represents the owner transfer command
+var ownerTransferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "Transfer ownership of an ENS name",
+	Long: `Transfer ownership of a name registered with the Ethereum Name Service (ENS) to another address.  For example:
+
+    ens owner transfer --to=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" enstest.eth
+
+Registry ownership and deed ownership are distinct.  The registry owner controls the name's records (resolver, subdomains etc.) and is what this command transfers by default.  The deed owner holds the funds locked when the name was won at auction and can reclaim registry ownership at any time.  To transfer the deed of a .eth name instead of the registry ownership supply --deed.  For names registered with the permanent registrar the registrant is transferred with 'ens owner set-registrant'.
+
+The keystore for the account that currently owns the name (or deed) must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to transfer ownership is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(ownerTransferToStr != "", exitBadInput, "Address to which to transfer ownership of the name is required")
+		if ownerTransferDeed {
+			assert(len(strings.Split(args[0], ".")) == 2, exitBadInput, "Deeds only exist for names directly under .eth")
+			state, err := ensClient.AuctionState(commandCtx, args[0])
+			assert(err == nil && state == "Owned", exitBadInput, "Domain not in a suitable state to transfer the deed")
+		}
+
+		newOwner, err := resolveAddress(ownerTransferToStr)
+		errCheck(err, quiet, "Failed to obtain new owner address")
+
+		// Fetch the current owner of the name or deed
+		var owner = ens.UnknownAddress
+		if ownerTransferDeed {
+			entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+			errCheck(err, quiet, "Cannot obtain information for that name")
+			deedContract, err := ens.DeedContract(client, &entry.Deed)
+			errCheck(err, quiet, "Failed to obtain deed contract")
+			owner, err = deedContract.Owner(callOpts())
+			errCheck(err, quiet, "Failed to obtain deed owner")
+		} else {
+			owner, err = registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+			errCheck(err, quiet, "Cannot obtain owner")
+		}
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+		assert(bytes.Compare(owner.Bytes(), newOwner.Bytes()) != 0, exitBadInput, "Name is already owned by that address")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		var tx *types.Transaction
+		if ownerTransferDeed {
+			opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+			tx, err = ensClient.TransferDeed(commandCtx, opts, args[0], newOwner)
+		} else {
+			session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
+			configureTransactOpts(&session.TransactOpts)
+			tx, err = session.SetOwner(ens.NameHash(args[0]), newOwner)
+		}
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"deed":  ownerTransferDeed,
+			"owner": newOwner.Hex()}, "Owner transfer")
+	},
+}
+
+func init() {
+	ownerCmd.AddCommand(ownerTransferCmd)
+
+	ownerTransferCmd.Flags().StringVarP(&ownerTransferToStr, "to", "t", "", "Address to which to transfer ownership of the name")
+	ownerTransferCmd.Flags().BoolVar(&ownerTransferDeed, "deed", false, "Transfer the registrar deed rather than the registry ownership")
+	addTransactionFlags(ownerTransferCmd, "Passphrase for the account that owns the name")
+}
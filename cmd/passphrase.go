@@ -0,0 +1,57 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/term"
+)
+
+var passphraseFile string
+var passphraseStdin bool
+
+// resolvePassphrase sets the passphrase from --passphrase-file or
+// --passphrase-stdin if supplied, otherwise prompting for it if a terminal
+// is attached.  It is only called if --passphrase is not supplied
+func resolvePassphrase(address common.Address) error {
+	switch {
+	case passphraseFile != "":
+		data, err := ioutil.ReadFile(passphraseFile)
+		if err != nil {
+			return err
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	case passphraseStdin:
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read passphrase from stdin: %v", err)
+		}
+		passphrase = strings.TrimRight(line, "\r\n")
+	case term.IsTerminal(int(os.Stdin.Fd())):
+		fmt.Fprintf(os.Stderr, "Passphrase for %s: ", address.Hex())
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return err
+		}
+		passphrase = string(data)
+	}
+	return nil
+}
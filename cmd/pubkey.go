@@ -0,0 +1,61 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+type pubkeyResult struct {
+	Name     string `json:"name"`
+	NameHash string `json:"namehash"`
+	X        string `json:"x"`
+	Y        string `json:"y"`
+}
+
+// pubkeyCmd represents the pubkey command
+var pubkeyCmd = &cobra.Command{
+	Use:   "pubkey",
+	Short: "Obtain the public key of an ENS name",
+	Long: `Obtain the secp256k1 public key associated with a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens pubkey enstest.eth
+
+In quiet mode this will return 0 if the name has a public key, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		x, y, err := ensClient.Pubkey(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain public key")
+		assert(x != [32]byte{} || y != [32]byte{}, exitNotFound, "No public key for that name")
+		if !quiet {
+			if jsonOutput {
+				outputJSON(&pubkeyResult{
+					Name:     args[0],
+					NameHash: nameHashHex(args[0]),
+					X:        hexutil.Encode(x[:]),
+					Y:        hexutil.Encode(y[:]),
+				})
+			} else {
+				fmt.Println("X:", hexutil.Encode(x[:]))
+				fmt.Println("Y:", hexutil.Encode(y[:]))
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(pubkeyCmd)
+}
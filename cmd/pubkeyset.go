@@ -0,0 +1,81 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var pubkeySetKey string
+
+// pubkeySetCmd represents the pubkey set command
+var pubkeySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the public key of an ENS name",
+	Long: `Set the secp256k1 public key of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens pubkey set --key=0x<64 bytes of hex> --passphrase="my secret passphrase" enstest.eth
+
+The key is the x and y coordinates of the public key as 0x-prefixed hex, optionally preceded by the uncompressed key prefix 04.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the public key is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(strings.HasPrefix(pubkeySetKey, "0x"), exitBadInput, "Key must be 0x-prefixed hex")
+		key, err := hex.DecodeString(pubkeySetKey[2:])
+		errCheck(badInput(err), quiet, "Invalid key")
+		if len(key) == 65 && key[0] == 0x04 {
+			key = key[1:]
+		}
+		assert(len(key) == 64, exitBadInput, "Key must be 64 bytes")
+		var x, y [32]byte
+		copy(x[:], key[:32])
+		copy(y[:], key[32:])
+
+		// Ensure that the name is in a suitable state
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set a public key")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		tx, err := ensClient.SetPubkey(commandCtx, opts, args[0], x, y)
+		errCheck(err, quiet, "Failed to set public key for that name")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"pubkey": pubkeySetKey}, "Pubkey set")
+	},
+}
+
+func init() {
+	pubkeyCmd.AddCommand(pubkeySetCmd)
+
+	pubkeySetCmd.Flags().StringVarP(&pubkeySetKey, "key", "k", "", "Public key as 0x-prefixed hex")
+	addTransactionFlags(pubkeySetCmd, "Passphrase for the account that owns the name")
+}
@@ -17,12 +17,26 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"time"
 
-	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/orinocopay/go-etherutils/ens"
 	"github.com/spf13/cobra"
 )
 
+type rawInfoResult struct {
+	Name             string `json:"name"`
+	LabelHash        string `json:"labelhash,omitempty"`
+	NameHash         string `json:"namehash"`
+	State            string `json:"state"`
+	Deed             string `json:"deed"`
+	RegistrationDate string `json:"registrationdate"`
+	Value            string `json:"value"`
+	HighestBid       string `json:"highestbid"`
+	Owner            string `json:"owner,omitempty"`
+	Resolver         string `json:"resolver,omitempty"`
+}
+
 // rawInfoCmd represents the info command
 var rawInfoCmd = &cobra.Command{
 	Use:   "rawinfo",
@@ -31,21 +45,40 @@ var rawInfoCmd = &cobra.Command{
 
     ens rawinfo enstest.eth
 
-In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
+In quiet mode this will return 0 if the domain is owned, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		registryContract, err := ens.RegistryContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-		registrarContract, err := ens.RegistrarContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain registrar contract")
-		state, deedAddress, registrationDate, value, highestBid, err := ens.Entry(registrarContract, client, args[0])
-		cli.ErrCheck(err, quiet, "Cannot obtain raw info")
+		entry, err := ensClient.AuctionEntry(commandCtx, args[0])
+		errCheck(err, quiet, "Cannot obtain raw info")
 		if quiet {
-			if state == "Owned" {
+			if entry.State == "Owned" {
 				os.Exit(0)
 			} else {
 				os.Exit(1)
 			}
+		} else if jsonOutput {
+			result := &rawInfoResult{
+				Name:             args[0],
+				NameHash:         nameHashHex(args[0]),
+				State:            entry.State,
+				Deed:             entry.Deed.Hex(),
+				RegistrationDate: entry.RegistrationDate.UTC().Format(time.RFC3339),
+				Value:            entry.Value.String(),
+				HighestBid:       entry.HighestBid.String(),
+			}
+			domain, err := ens.Domain(args[0])
+			if err == nil {
+				result.LabelHash = common.Hash(ens.LabelHash(domain)).Hex()
+			}
+			registryOwner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+			if err == nil {
+				result.Owner = registryOwner.Hex()
+			}
+			resolver, err := registryContract.Resolver(callOpts(), ens.NameHash(args[0]))
+			if err == nil {
+				result.Resolver = resolver.Hex()
+			}
+			outputJSON(result)
 		} else {
 			fmt.Println("Hashes")
 			fmt.Println("~~~~~~")
@@ -58,18 +91,18 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 			fmt.Println("NameHash:", hex.EncodeToString(nameHash[:]))
 			fmt.Println("Entry")
 			fmt.Println("~~~~~")
-			fmt.Println("State:", state)
-			fmt.Println("Deed address:", deedAddress.Hex())
-			fmt.Println("Registration date:", registrationDate)
-			fmt.Println("Value:", value)
-			fmt.Println("Highest bid:", highestBid)
+			fmt.Println("State:", entry.State)
+			fmt.Println("Deed address:", entry.Deed.Hex())
+			fmt.Println("Registration date:", entry.RegistrationDate)
+			fmt.Println("Value:", entry.Value)
+			fmt.Println("Highest bid:", entry.HighestBid)
 			fmt.Println("Registry")
 			fmt.Println("~~~~~~~~")
-			registryOwner, err := registryContract.Owner(nil, nameHash)
+			registryOwner, err := registryContract.Owner(callOpts(), nameHash)
 			if err == nil {
 				fmt.Println("Owner:", registryOwner.Hex())
 			}
-			resolver, err := registryContract.Resolver(nil, nameHash)
+			resolver, err := registryContract.Resolver(callOpts(), nameHash)
 			if err == nil {
 				fmt.Println("Resolver:", resolver.Hex())
 			}
@@ -21,6 +21,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type resolverResult struct {
+	Name     string `json:"name"`
+	NameHash string `json:"namehash"`
+	Resolver string `json:"resolver"`
+}
+
 // resolverCmd represents the resolver command
 var resolverCmd = &cobra.Command{
 	Use:   "resolver",
@@ -29,19 +35,24 @@ var resolverCmd = &cobra.Command{
 
     ens resolver enstest.eth
 
-In quiet mode this will return 0 if the name has a resolver, otherwise 1.`,
+In quiet mode this will return 0 if the name has a resolver, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		registrarContract, err := ens.RegistrarContract(client)
-		inState, err := ens.NameInState(registrarContract, client, args[0], "Owned")
-		cli.ErrAssert(inState, err, quiet, "Name not in a suitable state to obtain the resolver")
+		state, err := ensClient.AuctionState(commandCtx, args[0])
+		cli.ErrAssert(state == "Owned", err, quiet, "Name not in a suitable state to obtain the resolver")
 
-		registryContract, err := ens.RegistryContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
 		resolver, err := ens.Resolver(registryContract, args[0])
-		cli.ErrCheck(err, quiet, "No resolver for that name")
+		errCheck(err, quiet, "No resolver for that name")
 		if !quiet {
-			fmt.Println(resolver.Hex())
+			if jsonOutput {
+				outputJSON(&resolverResult{
+					Name:     args[0],
+					NameHash: nameHashHex(args[0]),
+					Resolver: resolver.Hex(),
+				})
+			} else {
+				fmt.Println(resolver.Hex())
+			}
 		}
 	},
 }
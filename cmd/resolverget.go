@@ -0,0 +1,74 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+type resolverGetResult struct {
+	Name       string   `json:"name"`
+	NameHash   string   `json:"namehash"`
+	Resolver   string   `json:"resolver"`
+	Interfaces []string `json:"interfaces"`
+}
+
+// resolverGetCmd represents the resolver get command
+var resolverGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain the resolver of an ENS name and the records it supports",
+	Long: `Obtain the resolver of a name registered with the Ethereum Name Service (ENS), along with the standard interfaces that it supports.  For example:
+
+    ens resolver get enstest.eth
+
+The interfaces show which records can be set on the resolver.
+
+The resolver of a name at an earlier block can be obtained with --block, giving a block number or one of latest, safe, finalized or earliest.  For example:
+
+    ens resolver get --block=14000000 enstest.eth
+
+In quiet mode this will return 0 if the name has a resolver, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		resolverAddress, err := ensClient.Resolver(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain resolver")
+		assert(resolverAddress != ens.UnknownAddress, exitNotFound, "No resolver for that name")
+		if quiet {
+			return
+		}
+		interfaces, err := supportedInterfaces(resolverAddress)
+		errCheck(err, quiet, "Failed to obtain interfaces supported by the resolver")
+
+		if jsonOutput {
+			outputJSON(&resolverGetResult{
+				Name:       args[0],
+				NameHash:   nameHashHex(args[0]),
+				Resolver:   resolverAddress.Hex(),
+				Interfaces: interfaces,
+			})
+			return
+		}
+		fmt.Printf("%-18s%s\n", "Resolver:", resolverAddress.Hex())
+		fmt.Printf("%-18s%s\n", "Interfaces:", orNone(strings.Join(interfaces, ", ")))
+	},
+}
+
+func init() {
+	resolverCmd.AddCommand(resolverGetCmd)
+
+	addBlockFlag(resolverGetCmd)
+}
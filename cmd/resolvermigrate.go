@@ -0,0 +1,156 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var resolverMigrateToStr string
+
+// resolverMigrateCmd represents the resolver migrate command
+var resolverMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move the records of an ENS name to a new resolver",
+	Long: `Copy the records of a name registered with the Ethereum Name Service (ENS) from its current resolver to a new resolver, and then make the new resolver the name's resolver.  For example:
+
+    ens resolver migrate --to=0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63 --passphrase="my secret passphrase" enstest.eth
+
+If the new resolver is not supplied then the public resolver for the network is used.  The records copied are the addresses for Ethereum and the other supported coins, the content hash, the standard text records and the public key; each record copied is reported.  The records are written to the new resolver before the registry is changed so that the name continues to resolve throughout.  If the new resolver supports multicall the records are set in a single transaction, otherwise a transaction is sent for each record.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transactions to migrate the resolver are sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		oldResolver, err := ensClient.Resolver(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain resolver")
+		assert(oldResolver != ens.UnknownAddress, exitNotFound, "No resolver for that name")
+		var newResolver common.Address
+		if resolverMigrateToStr == "" {
+			newResolver, err = publicResolverAddress()
+			errCheck(err, quiet, "No public resolver for that network")
+		} else {
+			newResolver, err = resolveAddress(resolverMigrateToStr)
+			errCheck(badInput(err), quiet, "Invalid resolver address")
+		}
+		assert(newResolver != oldResolver, exitBadInput, "Name already uses that resolver")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		calls := resolverMigrateCalls(args[0])
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		if len(calls) > 0 {
+			sendResolverRecordCalls(newResolver, opts, args[0], calls, "Copying", "Resolver migrate records")
+		} else if !quiet {
+			fmt.Println("No records to copy")
+		}
+
+		session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
+		configureTransactOpts(&session.TransactOpts)
+		session.TransactOpts.Nonce = opts.Nonce
+		tx, err := ens.SetResolver(session, args[0], &newResolver)
+		errCheck(err, quiet, "Failed to send transaction")
+		if !quiet {
+			fmt.Println("Resolver is", newResolver.Hex())
+		}
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"resolver": newResolver.Hex(),
+			"records":  len(calls)}, "Resolver migrate")
+	},
+}
+
+// resolverMigrateCalls creates the calls to copy the records of a name from
+// its current resolver.  Records that the resolver does not support are
+// skipped
+func resolverMigrateCalls(name string) []*resolverRecordCall {
+	node := ens.NameHash(name)
+	calls := make([]*resolverRecordCall, 0)
+
+	// Resolvers that predate multicoin addresses only have the Ethereum address
+	if address, err := ensClient.Address(commandCtx, name); err == nil && address != ens.UnknownAddress {
+		calls = append(calls, &resolverRecordCall{
+			description: "address " + address.Hex(),
+			method:      "setAddr",
+			params:      []interface{}{node, new(big.Int).SetUint64(ethCoinType), address.Bytes()},
+		})
+	}
+	coinTypes := make([]uint64, 0, len(coins))
+	for coinType := range coins {
+		coinTypes = append(coinTypes, coinType)
+	}
+	sort.Slice(coinTypes, func(i, j int) bool { return coinTypes[i] < coinTypes[j] })
+	for _, coinType := range coinTypes {
+		if data, err := ensClient.MultiAddress(commandCtx, name, coinType); err == nil && len(data) > 0 {
+			calls = append(calls, &resolverRecordCall{
+				description: fmt.Sprintf("%s address", coins[coinType].symbol),
+				method:      "setAddr",
+				params:      []interface{}{node, new(big.Int).SetUint64(coinType), data},
+			})
+		}
+	}
+	if hash, err := ensClient.Contenthash(commandCtx, name); err == nil && len(hash) > 0 {
+		description := "content hash"
+		if content, err := decodeContenthash(hash); err == nil {
+			description += " " + content
+		}
+		calls = append(calls, &resolverRecordCall{
+			description: description,
+			method:      "setContenthash",
+			params:      []interface{}{node, hash},
+		})
+	}
+	for _, key := range standardTextKeys {
+		if value, err := ensClient.Text(commandCtx, name, key); err == nil && value != "" {
+			calls = append(calls, &resolverRecordCall{
+				description: fmt.Sprintf("text record %s", key),
+				method:      "setText",
+				params:      []interface{}{node, key, value},
+			})
+		}
+	}
+	if x, y, err := ensClient.Pubkey(commandCtx, name); err == nil && (x != [32]byte{} || y != [32]byte{}) {
+		calls = append(calls, &resolverRecordCall{
+			description: "public key",
+			method:      "setPubkey",
+			params:      []interface{}{node, x, y},
+		})
+	}
+	return calls
+}
+
+func init() {
+	resolverCmd.AddCommand(resolverMigrateCmd)
+
+	resolverMigrateCmd.Flags().StringVar(&resolverMigrateToStr, "to", "", "Address of the new resolver (defaults to the public resolver)")
+	addTransactionFlags(resolverMigrateCmd, "Passphrase for the account that owns the name")
+}
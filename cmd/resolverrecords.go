@@ -0,0 +1,84 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// multiCoinInterface is the EIP-165 interface ID of resolvers that support
+// addresses for coins other than Ether
+var multiCoinInterface = [4]byte{0xf1, 0xcb, 0x7e, 0x06}
+
+// multicallInterface is the EIP-165 interface ID of resolvers that support multicall
+var multicallInterface = [4]byte{0xac, 0x96, 0x50, 0xd8}
+
+// multicallNodeCheckInterface is the EIP-165 interface ID of resolvers that
+// support multicallWithNodeCheck as well as multicall, such as the current
+// public resolver
+var multicallNodeCheckInterface = [4]byte{0x4f, 0xbf, 0x04, 0x33}
+
+// resolverInterface is an EIP-165 interface that a resolver can support
+type resolverInterface struct {
+	name string
+	id   [4]byte
+}
+
+// resolverInterfaces are the standard resolver interfaces
+var resolverInterfaces = []resolverInterface{
+	{"addr", [4]byte{0x3b, 0x3b, 0x57, 0xde}},
+	{"multicoin addr", multiCoinInterface},
+	{"name", [4]byte{0x69, 0x1f, 0x34, 0x31}},
+	{"text", [4]byte{0x59, 0xd1, 0xd4, 0x3c}},
+	{"contenthash", [4]byte{0xbc, 0x1c, 0x58, 0xd1}},
+	{"abi", [4]byte{0x22, 0x03, 0xab, 0x56}},
+	{"pubkey", [4]byte{0xc8, 0x69, 0x02, 0x33}},
+	{"wildcard", [4]byte{0x90, 0x61, 0xb9, 0x23}},
+	{"multicall", multicallInterface},
+	{"multicall with node check", multicallNodeCheckInterface},
+}
+
+// checkResolverInterface returns an error if a resolver does not support the
+// named standard interface, so that records are not sent to a resolver that
+// would reject them
+func checkResolverInterface(resolverAddress common.Address, interfaceName string) error {
+	var id [4]byte
+	for _, resolverInterface := range resolverInterfaces {
+		if resolverInterface.name == interfaceName {
+			id = resolverInterface.id
+		}
+	}
+	supported, err := ensClient.SupportsInterface(commandCtx, resolverAddress, id)
+	if err != nil || !supported {
+		return fmt.Errorf("resolver %s does not support %s records; use 'ens resolver set' to change to a resolver that does, such as the public resolver", resolverAddress.Hex(), interfaceName)
+	}
+	return nil
+}
+
+// supportedInterfaces returns the names of the standard interfaces supported by a resolver
+func supportedInterfaces(resolverAddress common.Address) ([]string, error) {
+	supported := make([]string, 0)
+	for _, resolverInterface := range resolverInterfaces {
+		isSupported, err := ensClient.SupportsInterface(commandCtx, resolverAddress, resolverInterface.id)
+		if err != nil {
+			return nil, err
+		}
+		if isSupported {
+			supported = append(supported, resolverInterface.name)
+		}
+	}
+	return supported, nil
+}
@@ -16,11 +16,12 @@ package cmd
 import (
 	"bytes"
 	"fmt"
-	"math/big"
 
-	etherutils "github.com/orinocopay/go-etherutils"
-	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ensclient "github.com/orinocopay/ens/client"
 	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -36,50 +37,72 @@ var resolverSetCmd = &cobra.Command{
 
 If the address is not supplied then the public resolver for the network will be used.
 
-The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+The resolver of a wrapped name is set through the NameWrapper by the owner of the wrapped name, and cannot be set if CANNOT_SET_RESOLVER has been burned.
 
-In quiet mode this will return 0 if the transaction to set the resolver is sent successfully, otherwise 1.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Ensure that the name is in a suitable state
-		if ens.DomainLevel(args[0]) == 1 {
-			cli.Assert(inState(args[0], "Owned"), quiet, "Domain not in a suitable state to set a resolver")
-		}
+The keystore for the account that owns the name, or the wrapped name, must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
+In quiet mode this will return 0 if the transaction to set the resolver is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
 		// Fetch the owner of the name
-		owner, err := registryContract.Owner(nil, ens.NameHash(args[0]))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Wrapped names are owned by the NameWrapper in the registry, so
+		// their resolver is set through the NameWrapper by the wrapped owner
+		wrapped := owner == ensClient.NameWrapper() && owner != ens.UnknownAddress
+
+		// Ensure that the name is in a suitable state.  Wrapped names are
+		// registered with the permanent registrar, and their expiry is
+		// checked by the NameWrapper
+		if ens.DomainLevel(args[0]) == 1 && !wrapped {
+			assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set a resolver")
+		}
+		if wrapped {
+			data, err := ensClient.WrappedData(commandCtx, args[0])
+			errCheck(err, quiet, "Failed to obtain wrapped name")
+			assert(data.Owner != ens.UnknownAddress, exitNotFound, "Wrapped name has no owner; it might have expired")
+			assert(data.Fuses&ensclient.CannotSetResolver == 0, exitBadInput, "CANNOT_SET_RESOLVER is burned for that name, so its resolver cannot be changed")
+			owner = data.Owner
+		}
 
 		// Fetch the wallet and account for the owner
 		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain account details for the owner of the domain")
-
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the domain")
 
-		// Set up our session
-		session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
 		// Set the resolver from either command-line or default
-		resolverAddress, err := ens.Resolve(client, resolverAddressStr)
-		if err != nil {
-			resolverAddress, err = ens.PublicResolver(client)
-			cli.ErrCheck(err, quiet, "No public resolver for that network")
+		var resolverAddress common.Address
+		if resolverAddressStr == "" {
+			resolverAddress, err = publicResolverAddress()
+			errCheck(err, quiet, "No public resolver for that network")
+		} else {
+			resolverAddress, err = resolveAddress(resolverAddressStr)
+			errCheck(badInput(err), quiet, "Invalid resolver address")
+		}
+		var tx *types.Transaction
+		if wrapped {
+			opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+			tx, err = ensClient.SetWrappedResolver(commandCtx, opts, args[0], resolverAddress)
+		} else {
+			session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
+			configureTransactOpts(&session.TransactOpts)
+			tx, err = ens.SetResolver(session, args[0], &resolverAddress)
 		}
-		tx, err := ens.SetResolver(session, args[0], &resolverAddress)
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
+		errCheck(err, quiet, "Failed to send transaction")
 		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
+			fmt.Println("Resolver is", resolverAddress.Hex())
 		}
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"resolver": resolverAddress.Hex()}, "Resolver set")
 	},
 }
 
 func init() {
 	resolverCmd.AddCommand(resolverSetCmd)
 
-	resolverSetCmd.Flags().StringVarP(&resolverAddressStr, "address", "a", "", "Address of the resolver")
+	resolverSetCmd.Flags().StringVarP(&resolverAddressStr, "address", "a", "", "Address of the resolver (defaults to the public resolver)")
 	addTransactionFlags(resolverSetCmd, "Passphrase for the account that owns the name")
 }
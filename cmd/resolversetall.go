@@ -0,0 +1,235 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+var resolverSetAllRecords string
+
+// resolverRecords is the specification of the records of a name read by resolver set-all
+type resolverRecords struct {
+	Address     string            `yaml:"address"`
+	Contenthash string            `yaml:"contenthash"`
+	Text        map[string]string `yaml:"text"`
+}
+
+// resolverRecordCall is a call to set a single record of a resolver
+type resolverRecordCall struct {
+	description string
+	method      string
+	params      []interface{}
+}
+
+// resolverSetAllCmd represents the resolver set-all command
+var resolverSetAllCmd = &cobra.Command{
+	Use:   "set-all",
+	Short: "Set a number of records of an ENS name at once",
+	Long: `Set the address, content hash and text records of a name registered with the Ethereum Name Service (ENS) from a records file.  For example:
+
+    ens resolver set-all --records=records.yaml --passphrase="my secret passphrase" enstest.eth
+
+The records file is JSON or YAML, for example:
+
+    address: 0x5FfC014343cd971B7eb70732021E26C35B744cc4
+    contenthash: ipfs://QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4
+    text:
+      url: https://www.example.com/
+      email: me@example.com
+
+Records that are not present in the file are left unchanged.  If the resolver supports multicall the records are set in a single transaction, otherwise a transaction is sent for each record.  Resolvers such as the current public resolver are asked to check that every record in the transaction is for the name, so that records cannot be written to another name.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transactions to set the records are sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(resolverSetAllRecords != "", exitBadInput, "Records file is required")
+		data, err := ioutil.ReadFile(resolverSetAllRecords)
+		errCheck(err, quiet, "Failed to read records file")
+		records := &resolverRecords{}
+		// YAML is a superset of JSON so this handles both
+		err = yaml.Unmarshal(data, records)
+		errCheck(err, quiet, "Failed to parse records file")
+		calls, err := resolverRecordCalls(args[0], records)
+		errCheck(badInput(err), quiet, "Invalid records")
+		assert(len(calls) > 0, exitBadInput, "No records to set")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		resolverAddress, err := ensClient.Resolver(commandCtx, args[0])
+		errCheck(err, quiet, "Failed to obtain resolver")
+		assert(resolverAddress != ens.UnknownAddress, exitNotFound, "No resolver for that name")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		sendResolverRecordCalls(resolverAddress, opts, args[0], calls, "Setting", "Resolver set all")
+	},
+}
+
+// sendResolverRecordCalls sends the calls to set the records of a name, in a
+// single transaction if the resolver supports multicall and otherwise in a
+// transaction for each record.  Multicalls are checked against the name by
+// the resolver where it supports this.  The nonce of opts is left at the
+// nonce following the transactions sent
+func sendResolverRecordCalls(resolver common.Address, opts *bind.TransactOpts, name string, calls []*resolverRecordCall, action string, msg string) {
+	if supportsMultiCoin, err := ensClient.SupportsInterface(commandCtx, resolver, multiCoinInterface); err != nil || !supportsMultiCoin {
+		calls = legacyAddressCalls(calls)
+		assert(len(calls) > 0, exitBadInput, "No records that the resolver supports")
+	}
+
+	supportsNodeCheck, err := ensClient.SupportsInterface(commandCtx, resolver, multicallNodeCheckInterface)
+	if err != nil {
+		supportsNodeCheck = false
+	}
+	supportsMulticall := supportsNodeCheck
+	if !supportsMulticall {
+		supportsMulticall, err = ensClient.SupportsInterface(commandCtx, resolver, multicallInterface)
+		if err != nil {
+			supportsMulticall = false
+		}
+	}
+
+	if supportsMulticall {
+		encoded := make([][]byte, len(calls))
+		for i, call := range calls {
+			encoded[i], err = ensclient.EncodeRecordCall(call.method, call.params...)
+			errCheck(err, quiet, "Failed to encode "+call.description)
+			if !quiet {
+				fmt.Println(action, call.description)
+			}
+		}
+		var tx *types.Transaction
+		if supportsNodeCheck {
+			tx, err = ensClient.MulticallWithNodeCheck(commandCtx, opts, resolver, name, encoded)
+		} else {
+			tx, err = ensClient.Multicall(commandCtx, opts, resolver, encoded)
+		}
+		errCheck(err, quiet, "Failed to set records for that name")
+		handleTransaction(tx, log.Fields{"name": name,
+			"records": len(calls)}, msg)
+		opts.Nonce = new(big.Int).SetUint64(tx.Nonce() + 1)
+		return
+	}
+
+	// Resolver does not support multicall so set each record separately
+	for _, call := range calls {
+		if !quiet {
+			fmt.Println(action, call.description)
+		}
+		encoded, err := ensclient.EncodeRecordCall(call.method, call.params...)
+		errCheck(err, quiet, "Failed to encode "+call.description)
+		tx, err := ensClient.SetRecord(commandCtx, opts, resolver, encoded)
+		errCheck(err, quiet, "Failed to set "+call.description)
+		handleTransaction(tx, log.Fields{"name": name,
+			"record": call.description}, msg)
+		opts.Nonce = new(big.Int).SetUint64(tx.Nonce() + 1)
+	}
+}
+
+// legacyAddressCalls converts the calls to set addresses for resolvers that
+// predate multicoin addresses, which can only hold an Ether address; the
+// addresses of other coins are left out
+func legacyAddressCalls(calls []*resolverRecordCall) []*resolverRecordCall {
+	converted := make([]*resolverRecordCall, 0, len(calls))
+	for _, call := range calls {
+		if call.method != "setAddr" {
+			converted = append(converted, call)
+			continue
+		}
+		if coinType := call.params[1].(*big.Int); coinType.Uint64() != ethCoinType {
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "WARNING: resolver does not support multicoin addresses; not setting %s\n", call.description)
+			}
+			continue
+		}
+		converted = append(converted, &resolverRecordCall{
+			description: call.description,
+			method:      "setAddr0",
+			params:      []interface{}{call.params[0], common.BytesToAddress(call.params[2].([]byte))},
+		})
+	}
+	return converted
+}
+
+// resolverRecordCalls creates the calls to set the records of a name
+func resolverRecordCalls(name string, records *resolverRecords) ([]*resolverRecordCall, error) {
+	node := ens.NameHash(name)
+	calls := make([]*resolverRecordCall, 0)
+	if records.Address != "" {
+		address, err := resolveAddress(records.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve address %s: %v", records.Address, err)
+		}
+		calls = append(calls, &resolverRecordCall{
+			description: "address " + address.Hex(),
+			method:      "setAddr",
+			params:      []interface{}{node, new(big.Int).SetUint64(ethCoinType), address.Bytes()},
+		})
+	}
+	if records.Contenthash != "" {
+		hash, err := encodeContenthash(records.Contenthash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content hash %s: %v", records.Contenthash, err)
+		}
+		calls = append(calls, &resolverRecordCall{
+			description: "content hash " + records.Contenthash,
+			method:      "setContenthash",
+			params:      []interface{}{node, hash},
+		})
+	}
+	keys := make([]string, 0, len(records.Text))
+	for key := range records.Text {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		calls = append(calls, &resolverRecordCall{
+			description: fmt.Sprintf("text record %s", key),
+			method:      "setText",
+			params:      []interface{}{node, key, records.Text[key]},
+		})
+	}
+	return calls, nil
+}
+
+func init() {
+	resolverCmd.AddCommand(resolverSetAllCmd)
+
+	resolverSetAllCmd.Flags().StringVar(&resolverSetAllRecords, "records", "", "JSON or YAML file containing the records to set")
+	addTransactionFlags(resolverSetAllCmd, "Passphrase for the account that owns the name")
+}
@@ -0,0 +1,75 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var rpcRetries int
+var rpcRetryDelay time.Duration
+
+// retryTransport retries HTTP requests to an Ethereum node that fail for
+// transient reasons, backing off exponentially between attempts.  Contract
+// reverts and other JSON-RPC errors are returned with a successful HTTP
+// status so are never retried
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends a request, retrying on network errors and on responses
+// that indicate the node is temporarily unavailable
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := rpcRetryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= rpcRetries || req.GetBody == nil || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		log.WithFields(log.Fields{"attempt": attempt + 1, "delay": delay}).Debug("Retrying RPC request")
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+}
+
+// retryable returns true if the result of a request indicates a transient failure
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
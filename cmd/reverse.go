@@ -0,0 +1,29 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// reverseCmd represents the reverse command
+var reverseCmd = &cobra.Command{
+	Use:   "reverse",
+	Short: "Manage ENS reverse records",
+	Long:  `Manage the reverse records that give the primary names of addresses in the Ethereum Name Service.`,
+}
+
+func init() {
+	RootCmd.AddCommand(reverseCmd)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// reverseClearCmd represents the reverse clear command
+var reverseClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the ENS name for an address",
+	Long: `Clear the primary name registered with the Ethereum Name Service (ENS) for an address, so that the address no longer resolves to a name.  For example:
+
+    ens reverse clear --passphrase="my secret passphrase" 0xED96dD3Be847b387217EF9DE5B20D8392A6cdf40
+
+The name itself is unaffected; only the reverse record of the address is cleared.  With --wait the reverse record is checked once the transaction has been mined.
+
+The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to clear the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(common.IsHexAddress(args[0]), exitBadInput, "Invalid address")
+		address := common.HexToAddress(args[0])
+
+		name, err := ensClient.Name(commandCtx, address)
+		assert(err == nil && name != "", exitNotFound, "No name set for that address")
+		if !quiet {
+			fmt.Println("Clearing name", name)
+		}
+
+		// Obtain the reverse registrar contract
+		reverseRegistrar, err := reverseRegistrarContract()
+		errCheck(err, quiet, "Failed to obtain reverse registrar contract")
+
+		// Only the address itself can clear its reverse record
+		wallet, account, err := obtainWalletAndAccount(address, passphrase)
+		errCheck(err, quiet, fmt.Sprintf("Failed to obtain account details for the address %s", args[0]))
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, fmt.Sprintf("Invalid gas price %s", gasPriceStr))
+
+		session := ens.CreateReverseRegistrarSession(chainID, &wallet, account, passphrase, reverseRegistrar, gasPrice)
+		configureTransactOpts(&session.TransactOpts)
+
+		tx, err := ens.SetName(session, "")
+		errCheck(err, quiet, "Failed to clear name for that address")
+		handleTransaction(tx, log.Fields{"address": args[0],
+			"name": name}, "Reverse clear")
+
+		if wait && sendsTransactions() {
+			name, err = ensClient.Name(commandCtx, address)
+			cli.Assert(err != nil || name == "", quiet, fmt.Sprintf("Name for that address is still %s", name))
+			if !quiet {
+				fmt.Println("Name cleared")
+			}
+		}
+	},
+}
+
+func init() {
+	reverseCmd.AddCommand(reverseClearCmd)
+
+	addTransactionFlags(reverseClearCmd, "Passphrase for the account of the address")
+}
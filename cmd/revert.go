@@ -0,0 +1,43 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	ensclient "github.com/orinocopay/ens/client"
+)
+
+// transactionRevertReason obtains the reason that a mined transaction was
+// reverted by replaying it as a call at the block in which it was mined
+func transactionRevertReason(tx *types.Transaction, blockNumber *big.Int) string {
+	msg, err := transactionCallMsg(tx)
+	if err != nil {
+		return ""
+	}
+	msg.Gas = tx.Gas()
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	_, err = client.CallContract(ctx, msg, blockNumber)
+	if err == nil {
+		return ""
+	}
+	if reason := ensclient.RevertReason(err); reason != "" {
+		return reason
+	}
+	return err.Error()
+}
@@ -14,24 +14,30 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"math/big"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	homedir "github.com/mitchellh/go-homedir"
+	ensclient "github.com/orinocopay/ens/client"
 	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
-	"github.com/orinocopay/go-etherutils/ens/registrarcontract"
 	"github.com/orinocopay/go-etherutils/ens/registrycontract"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -39,6 +45,10 @@ import (
 var cfgFile string
 var logFile string
 var quiet bool
+var jsonOutput bool
+var outputFormat string
+var outputTemplate *template.Template
+var dryRun bool
 var connection string
 
 var client *ethclient.Client
@@ -47,17 +57,29 @@ var chainID *big.Int
 // Common command-line arguments
 var passphrase string
 var gasPriceStr string
+var maxFeeStr string
+var priorityFeeStr string
 var nonce int64
+var gasLimit uint64
+var wait bool
+var waitTimeout time.Duration
+var ledger bool
+var outputTxFile string
+var hdPath string
 
 // Common contracts
 var registryContract *registrycontract.RegistryContract
-var registrarContract *registrarcontract.RegistrarContract
+
+// ensClient carries out the core ENS operations
+var ensClient *ensclient.Client
 
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
-	Use:              "ens",
-	Short:            "manage ENS entries",
-	Long:             `Manage entries for the Ethereum Name Service (ENS).  Details of each indiidual command are available in the help files for the relevant command`,
+	Use:   "ens",
+	Short: "manage ENS entries",
+	Long: `Manage entries for the Ethereum Name Service (ENS).  Details of each indiidual command are available in the help files for the relevant command
+
+Commands exit with 0 on success.  Failures exit with 3 for invalid input, 4 if something is not found, 5 for insufficient funds, 6 if a transaction reverts, 7 for network errors, 2 if a transaction is not mined within --wait-timeout and otherwise 1.`,
 	PersistentPreRun: persistentPreRun,
 }
 
@@ -67,68 +89,107 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Ensure that the first argument is present
-	if len(args) == 0 {
-		cli.Err(quiet, "This command requires a name")
-	}
-	if args[0] == "" {
-		cli.Err(quiet, "This command requires a name")
-	}
-
-	if cmd.Name() != "nonce" {
-		// Add '.eth' to the end of the name if not present
-		if !strings.HasSuffix(args[0], ".eth") {
-			// Might be a hex address
-			if len(args[0]) == 40 || len(args[0]) == 42 {
-				_, err := hex.DecodeString(args[0])
-				if err != nil {
-					// Might be a hex address with leading 0x
-					if len(args[0]) > 2 && strings.HasPrefix(args[0], "0x") {
-						_, err = hex.DecodeString(args[0][2:])
-					}
-					if err != nil {
-						// Not a valid hex string
-						args[0] += ".eth"
-					}
-				}
-			} else {
-				// Not a hex string
-				args[0] += ".eth"
+	warnNoNormalize()
+	if requiresName(cmd) {
+		// Ensure that the first argument is present
+		if len(args) == 0 {
+			fail(exitBadInput, "This command requires a name")
+		}
+		if args[0] == "" {
+			fail(exitBadInput, "This command requires a name")
+		}
+
+		var err error
+		args[0], err = cleanName(args[0])
+		errCheck(badInput(err), quiet, "Invalid name")
+		if cmd.Name() != "nonce" {
+			if label := cmd.Flags().Lookup("label"); (label == nil || !label.Changed) && !dnsNameCommands[cmd.CommandPath()] {
+				args[0] = expandName(args[0])
+			}
+			if !common.IsHexAddress(args[0]) {
+				args[0], err = normalizeName(args[0])
+				errCheck(badInput(err), quiet, "Invalid name")
 			}
 		}
 	}
 
-	// Set the log file if set, otherwise ignore
-	if logFile != "" {
-		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
-		cli.ErrCheck(err, quiet, "Failed to open log file")
-		log.SetOutput(f)
-		log.SetFormatter(&log.JSONFormatter{})
-	} else {
-		log.SetOutput(ioutil.Discard)
+	err := configureLogging(cmd)
+	errCheck(err, quiet, "Failed to configure logging")
+	if outputFormat != "" {
+		outputTemplate, err = template.New("format").Parse(outputFormat)
+		errCheck(badInput(err), quiet, "Invalid --format template")
+		// Templates are rendered from the structured output
+		jsonOutput = true
+	}
+	startTimeout(cmd)
+
+	if connectionlessCommands[cmd.CommandPath()] {
+		return
 	}
 
 	// Create a connection to an Ethereum node
-	var err error
-	client, err = ethclient.Dial(connection)
-	cli.ErrCheck(err, quiet, "Failed to connect to Ethereum")
+	if !cmd.Flags().Changed("connection") && os.Getenv("ETH_CONNECTION") != "" {
+		connection = os.Getenv("ETH_CONNECTION")
+	}
+	client, err = dialConnection(connection)
+	errCheck(err, quiet, "Failed to connect to Ethereum")
 	// Fetch the chain ID
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(commandCtx, 5*time.Second)
 	defer cancel()
-	chainID, err = client.NetworkID(ctx)
-	cli.ErrCheck(err, quiet, "Failed to obtain chain ID")
+	chainID, err = client.ChainID(ctx)
+	errCheck(err, quiet, "Failed to obtain chain ID")
 
-	// Set up the common contracts
-	registrarContract, err = ens.RegistrarContract(client)
-	cli.ErrCheck(err, quiet, "Cannot obtain ENS registrar contract")
-	registryContract, err = ens.RegistryContract(client)
-	cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+	// Select the network, ensuring that it matches the node if supplied
+	if networkName != "" {
+		currentNetwork, err = networkByName(networkName)
+		errCheck(badInput(err), quiet, "Invalid network")
+		assert(currentNetwork.chainID == chainID.Uint64(), exitBadInput, fmt.Sprintf("Connection is to chain %v but network %s is chain %d", chainID, currentNetwork.name, currentNetwork.chainID))
+	} else {
+		currentNetwork = networkByChainID(chainID.Uint64())
+	}
+
+	if blockStr != "" {
+		readBlock, err = resolveBlock(blockStr)
+		errCheck(badInput(err), quiet, "Invalid block")
+	}
+
+	// Set up the common contracts.  The registry shares the ENS client's
+	// cache of owners and resolvers
+	ensClient, err = ensclient.New(client, chainID, ensClientConfig())
+	errCheck(err, quiet, "Cannot create ENS client")
+	registryContract, err = registrycontract.NewRegistryContract(ensClient.Registry(), ensClient.Backend())
+	errCheck(err, quiet, "Cannot obtain ENS registry contract")
+}
+
+// ensClientConfig returns the configuration of the ENS client for the current network
+func ensClientConfig() *ensclient.Config {
+	if currentNetwork == nil {
+		return &ensclient.Config{
+			BlockNumber: readBlock,
+			CCIPTimeout: ccipTimeout,
+			CCIPAllow:   ccipAllow,
+		}
+	}
+	return &ensclient.Config{
+		Registry:       currentNetwork.registry,
+		BaseRegistrar:  currentNetwork.baseRegistrar,
+		Controller:     currentNetwork.controller,
+		NameWrapper:    currentNetwork.nameWrapper,
+		BulkRenewal:    currentNetwork.bulkRenewal,
+		LegacyRegistry: currentNetwork.legacyRegistry,
+		BlockNumber:    readBlock,
+		CCIPTimeout:    ccipTimeout,
+		CCIPAllow:      ccipAllow,
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
+	addEstimateCommands()
+	err := RootCmd.Execute()
+	cancelCommand()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -138,10 +199,25 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flgs
-	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cmd.yaml)")
-	RootCmd.PersistentFlags().StringVarP(&logFile, "log", "l", "", "log activity to the named file")
+	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file, which can override the contract addresses of networks and add new networks under 'networks' (default is $HOME/.cmd.yaml)")
+	RootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "log activity to the named file rather than stderr")
+	// --log is kept, along with its -l shorthand, for existing scripts
+	RootCmd.PersistentFlags().StringVarP(&logFile, "log", "l", "", "log activity to the named file rather than stderr")
+	RootCmd.PersistentFlags().MarkHidden("log")
+	RootCmd.PersistentFlags().StringVar(&verbosity, "verbosity", "warn", "level of detail to log: error, warn, info or debug (debug includes requests to the Ethereum node; defaults to info with --log-file)")
+	RootCmd.PersistentFlags().BoolVar(&noNormalize, "no-normalize", false, "use names exactly as given, other than trimming surrounding whitespace, rather than normalizing them with UTS-46, which is not the full ENSIP-15 normalization (for debugging; names that are not normalized cannot be found by other ENS clients)")
 	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "no output")
-	RootCmd.PersistentFlags().StringVarP(&connection, "connection", "c", "https://api.orinocopay.com:8546/", "path to the Ethereum connection")
+	RootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output results as JSON")
+	RootCmd.PersistentFlags().StringVar(&outputFormat, "format", "", "output results through a Go template using the fields of the JSON output (see 'ens help format')")
+	RootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print transactions rather than sending them")
+	RootCmd.PersistentFlags().StringVarP(&connection, "connection", "c", "https://api.orinocopay.com:8546/", "path to the Ethereum connection: an HTTP or WebSocket URL, or an IPC path (defaults to $ETH_CONNECTION if set)")
+	RootCmd.PersistentFlags().StringVar(&keystoreDir, "keystore", "", "directory of the keystore holding accounts (defaults to $ETH_KEYSTORE if set, otherwise the default keystore)")
+	RootCmd.PersistentFlags().StringVar(&networkName, "network", "", "network to use (mainnet, goerli or sepolia); defaults to the network of the connection")
+	RootCmd.PersistentFlags().DurationVar(&ccipTimeout, "ccip-timeout", 10*time.Second, "timeout for requests to offchain (CCIP-Read) gateways")
+	RootCmd.PersistentFlags().StringSliceVar(&ccipAllow, "ccip-allow", nil, "comma-separated hosts of offchain (CCIP-Read) gateways that may be used (default all)")
+	RootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "maximum time for a command to run, excluding time waiting for transactions with --wait; calls to the Ethereum node made after this fail (0 for no limit)")
+	RootCmd.PersistentFlags().IntVar(&rpcRetries, "rpc-retries", 3, "number of times to retry HTTP requests to the Ethereum node that fail for transient reasons")
+	RootCmd.PersistentFlags().DurationVar(&rpcRetryDelay, "rpc-retry-delay", 500*time.Millisecond, "delay before the first retry of a request to the Ethereum node, doubling for each subsequent retry")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -167,33 +243,254 @@ func initConfig() {
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	} else if cfgFile != "" {
+		errCheck(err, quiet, "Failed to read config file")
 	}
+	errCheck(loadNetworkConfig(), quiet, "Invalid network in config file")
 }
 
 //
 // Helpers
 //
 
+// dialConnection connects to an Ethereum node over HTTP, WebSocket or IPC
+// depending on the scheme of the connection
+func dialConnection(connection string) (*ethclient.Client, error) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	u, err := url.Parse(connection)
+	if err != nil {
+		return nil, badInput(err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		httpClient := &http.Client{Transport: &retryTransport{base: &loggingTransport{base: http.DefaultTransport}}}
+		client, err := rpc.DialOptions(ctx, connection, rpc.WithHTTPClient(httpClient))
+		if err != nil {
+			return nil, networkFailure(err)
+		}
+		return ethclient.NewClient(client), nil
+	case "ws", "wss":
+		client, err := rpc.DialContext(ctx, connection)
+		if err != nil {
+			return nil, networkFailure(err)
+		}
+		return ethclient.NewClient(client), nil
+	case "", "ipc":
+		// IPC connections are given as a filesystem path
+		client, err := rpc.DialIPC(ctx, u.Path)
+		if err != nil {
+			return nil, networkFailure(err)
+		}
+		return ethclient.NewClient(client), nil
+	default:
+		return nil, badInput(fmt.Errorf("unsupported connection scheme %s", u.Scheme))
+	}
+}
+
 // Add flags for commands that carry out transactions
 func addTransactionFlags(cmd *cobra.Command, passphraseExplanation string) {
 	cmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", passphraseExplanation)
-	cmd.Flags().StringVarP(&gasPriceStr, "gasprice", "g", "4 GWei", "Gas price for the transaction")
-	cmd.Flags().Int64VarP(&nonce, "nonce", "n", -1, "Nonce for the transaction; -1 is auto-select")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "File containing the passphrase")
+	cmd.Flags().BoolVar(&passphraseStdin, "passphrase-stdin", false, "Read the passphrase from the first line of stdin")
+	cmd.Flags().StringVarP(&gasPriceStr, "gasprice", "g", "", "Gas price for the transaction (defaults to the fees suggested by the node)")
+	cmd.Flags().Float64Var(&gasMultiplier, "gas-multiplier", 1, "Multiplier for the fees suggested by the node, for faster inclusion")
+	cmd.Flags().StringVar(&maxFeeStr, "max-fee", "", "Maximum fee per gas for an EIP-1559 transaction (overrides gas price)")
+	cmd.Flags().StringVar(&priorityFeeStr, "priority-fee", "", "Priority fee per gas for an EIP-1559 transaction (defaults to the node's suggestion)")
+	cmd.Flags().Int64VarP(&nonce, "nonce", "n", -1, "Nonce for the transaction; -1 is the account's pending nonce")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Replace the account's first pending transaction, using its nonce and higher fees")
+	cmd.Flags().Uint64Var(&gasLimit, "gas-limit", 0, "Gas limit for the transaction; 0 is estimate")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for the transaction to be mined, exiting with 6 if it is reverted or 2 if it is not mined within --wait-timeout")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 10*time.Minute, "Time to wait for the transaction to be mined")
+	cmd.Flags().StringVar(&outputTxFile, "output-tx", "", "Write the unsigned transaction to the named file rather than sending it, one JSON object per line for commands that create several transactions")
+	cmd.Flags().BoolVar(&skipBalanceCheck, "skip-balance-check", false, "Send a transaction that carries value without checking that the account can pay for it")
+	cmd.Flags().BoolVar(&confirm, "confirm", confirmByDefault(), "Ask for confirmation before sending the transaction (defaults to true if a terminal is attached)")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Send the transaction without asking for confirmation")
+	cmd.Flags().BoolVar(&ledger, "ledger", false, "Sign the transaction with a Ledger hardware wallet rather than a local keystore")
+	cmd.Flags().StringVar(&clefEndpoint, "clef", "", "Sign the transaction with the clef instance at the given IPC path or URL rather than a local keystore")
+	cmd.Flags().StringVar(&hdPath, "hd-path", "m/44'/60'/0'/0/0", "HD derivation path of the account on the Ledger")
 }
 
-func inState(name string, state string) (inState bool) {
-	// Ensure that the name is in a suitable state
-	inState, err := ens.NameInState(registrarContract, client, name, state)
-	if err != nil {
-		inState = false
+// namelessCommands are the commands that do not operate on a name
+var namelessCommands = map[string]bool{
+	"ens account import": true,
+	"ens account list":   true,
+	"ens bid list":       true,
+	"ens dnsname":        true,
+	"ens gas":            true,
+	"ens name check":     true,
+	"ens name resolve":   true,
+	"ens tx send":        true,
+	"ens version":        true,
+}
+
+// dnsNameCommands are the commands that operate on DNS names, which are
+// not expanded with '.eth'
+var dnsNameCommands = map[string]bool{
+	"ens name import-dns": true,
+}
+
+// connectionlessCommands are the commands that do not need a connection to an Ethereum node
+var connectionlessCommands = map[string]bool{
+	"ens account import": true,
+	"ens account list":   true,
+	"ens dnsname":        true,
+	"ens name check":     true,
+	"ens version":        true,
+}
+
+// requiresName returns true if the command operates on a name supplied as
+// its first argument rather than on names read from a file or no name at all
+func requiresName(cmd *cobra.Command) bool {
+	if namelessCommands[cmd.CommandPath()] {
+		return false
+	}
+	if fromFile := cmd.Flags().Lookup("from-file"); fromFile != nil && fromFile.Changed {
+		return false
 	}
-	return
+	return true
 }
 
-func obtainWalletAndAccount(address common.Address, passphrase string) (wallet accounts.Wallet, account *accounts.Account, err error) {
+// expandName adds '.eth' to the end of a name if not present, unless it is
+// a hex address
+func expandName(name string) string {
+	if strings.HasSuffix(name, ".eth") {
+		return name
+	}
+	// Might be a hex address
+	if len(name) == 40 || len(name) == 42 {
+		_, err := hex.DecodeString(name)
+		if err != nil {
+			// Might be a hex address with leading 0x
+			if len(name) > 2 && strings.HasPrefix(name, "0x") {
+				_, err = hex.DecodeString(name[2:])
+			}
+			if err != nil {
+				// Not a valid hex string
+				name += ".eth"
+			}
+		}
+	} else {
+		// Not a hex string
+		name += ".eth"
+	}
+	return name
+}
+
+// inState returns true if a name is in a state in the auction registrar.
+// A name is also Owned if the name directly under .eth of which it is part
+// is registered with the permanent registrar and has not expired
+func inState(name string, state string) bool {
+	current, err := ensClient.AuctionState(commandCtx, name)
+	if err == nil && current == state {
+		return true
+	}
+	return state == "Owned" && registered(name)
+}
+
+// registered returns true if the name directly under .eth of which a name
+// is part is registered with the permanent registrar and has not expired
+func registered(name string) bool {
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 || labels[len(labels)-1] != "eth" {
+		return false
+	}
+	expiry, err := ensClient.Expiry(commandCtx, strings.Join(labels[len(labels)-2:], "."))
+	if err != nil || expiry.IsZero() {
+		return false
+	}
+	now, err := chainTime()
+	return err == nil && now.Before(expiry)
+}
+
+// outputJSON prints the result of a command as a single JSON object, or
+// through the template given by --format
+func outputJSON(result interface{}) {
+	data, err := json.Marshal(result)
+	errCheck(err, quiet, "Failed to generate JSON output")
+	if outputTemplate == nil {
+		fmt.Println(string(data))
+		return
+	}
+	// Templates use the same fields as the JSON output
+	var fields interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	errCheck(decoder.Decode(&fields), quiet, "Failed to generate output")
+	var output bytes.Buffer
+	errCheck(outputTemplate.Execute(&output, fields), quiet, "Failed to generate output with --format")
+	fmt.Println(strings.TrimSuffix(output.String(), "\n"))
+}
+
+// nameHashHex returns the namehash of a name as a 0x-prefixed hex string
+func nameHashHex(name string) string {
+	return common.Hash(ens.NameHash(name)).Hex()
+}
+
+// obtainWalletAndAccount obtains the wallet and account for an address, from
+// either the local keystore, a clef instance if --clef is supplied or a
+// Ledger if --ledger is supplied.  If --output-tx is supplied the wallet does
+// not sign transactions.  If no passphrase is supplied it is obtained from
+// elsewhere with resolvePassphrase.  Accounts are found in the keystore given
+// by --keystore or $ETH_KEYSTORE if set, otherwise in the default keystore
+func obtainWalletAndAccount(address common.Address, accountPassphrase string) (wallet accounts.Wallet, account *accounts.Account, err error) {
+	if signsElsewhere() {
+		// Transaction will be signed elsewhere, or not at all when estimating
+		outputTxFrom = address
+		return &unsignedWallet{address: address}, &accounts.Account{Address: address}, nil
+	}
+	if clefEndpoint != "" {
+		return obtainClefWalletAndAccount(address)
+	}
+	if ledger {
+		return obtainLedgerWalletAndAccount(address)
+	}
+	if accountPassphrase == "" {
+		if err = resolvePassphrase(address); err != nil {
+			return nil, nil, err
+		}
+		accountPassphrase = passphrase
+	}
+	if dir := keystorePath(); dir != "" {
+		return obtainKeystoreWalletAndAccount(dir, address, accountPassphrase)
+	}
 	wallet, err = cli.ObtainWallet(chainID, address)
-	if err == nil {
-		account, err = cli.ObtainAccount(&wallet, &address, passphrase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v (searched the default keystore; use --keystore or $ETH_KEYSTORE to search another directory)", err)
 	}
+	account, err = cli.ObtainAccount(&wallet, &address, accountPassphrase)
 	return wallet, account, err
 }
+
+// obtainLedgerWalletAndAccount obtains the account at the HD path of the
+// first connected Ledger, which must match the address
+func obtainLedgerWalletAndAccount(address common.Address) (accounts.Wallet, *accounts.Account, error) {
+	path, err := accounts.ParseDerivationPath(hdPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, nil, err
+	}
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, nil, errors.New("no Ledger found")
+	}
+	wallet := wallets[0]
+	if err = wallet.Open(""); err != nil {
+		return nil, nil, err
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account.Address != address {
+		return nil, nil, fmt.Errorf("account at %s on the Ledger is %s not %s", hdPath, account.Address.Hex(), address.Hex())
+	}
+	// Ledger signing is confirmed on the device, and the passphrase is ignored
+	if !quiet {
+		fmt.Println("Confirm the transaction on the Ledger")
+	}
+	return wallet, &account, nil
+}
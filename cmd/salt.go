@@ -0,0 +1,66 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// minSaltLength is the number of characters below which a salt is
+// considered easy to guess
+const minSaltLength = 12
+
+// obtainBidSalt returns the salt for a bid.  If no salt is supplied a random
+// one is generated and shown; it is also kept in the bid journal.  A
+// supplied salt that looks easy to guess is warned about
+func obtainBidSalt(salt string) string {
+	if salt != "" {
+		if reason := weakSalt(salt); reason != "" && !quiet {
+			fmt.Fprintf(os.Stderr, "WARNING: salt %s; a bid with a guessable salt can be discovered before it is revealed\n", reason)
+		}
+		return salt
+	}
+	// In quiet mode the generated salt is not shown so it must be journalled
+	// if the bid is placed
+	assert(!quiet || bidJournal != "" || dryRun || estimateOnly, exitBadInput, "Salt is required in quiet mode without a bid journal")
+	random := make([]byte, 16)
+	_, err := rand.Read(random)
+	errCheck(err, quiet, "Failed to generate salt")
+	salt = hexutil.Encode(random)
+	if !quiet {
+		fmt.Println("Generated salt:", salt)
+		fmt.Println("Keep a note of this salt; it is needed to reveal the bid")
+	}
+	return salt
+}
+
+// weakSalt returns why a salt looks easy to guess, or an empty string if it does not
+func weakSalt(salt string) string {
+	if utf8.RuneCountInString(salt) < minSaltLength {
+		return fmt.Sprintf("is shorter than %d characters", minSaltLength)
+	}
+	distinct := make(map[rune]bool)
+	for _, char := range salt {
+		distinct[char] = true
+	}
+	if len(distinct) < 5 {
+		return "has very few different characters"
+	}
+	return ""
+}
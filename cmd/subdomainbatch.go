@@ -0,0 +1,260 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/orinocopay/go-etherutils/cli"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+)
+
+var subdomainCreateFromFile string
+
+// subdomainRow is a subdomain to create, read from a file
+type subdomainRow struct {
+	line    int
+	label   string
+	owner   common.Address
+	address *common.Address
+}
+
+type subdomainBatchResult struct {
+	Name          string `json:"name"`
+	Owner         string `json:"owner"`
+	Address       string `json:"address,omitempty"`
+	TransactionID string `json:"transactionid,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// subdomainCreator creates subdomains of a domain through either the
+// registry or, for a wrapped domain, the NameWrapper
+type subdomainCreator struct {
+	domain  string
+	wrapped bool
+	owner   common.Address
+	opts    *bind.TransactOpts
+	// expiry is the expiry of a wrapped domain, which its subdomains are
+	// given as the NameWrapper does not allow them to outlive it
+	expiry time.Time
+}
+
+// readSubdomainRows reads the subdomains to create from a file with lines
+// of the form label,owner[,address]
+func readSubdomainRows(path string) ([]*subdomainRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows := make([]*subdomainRow, 0)
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected label,owner[,address]", line)
+		}
+		label, err := cleanName(record[0])
+		if err == nil {
+			label, err = normalizeName(label)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid label: %v", line, err)
+		}
+		if label == "" || strings.Contains(label, ".") {
+			return nil, fmt.Errorf("line %d: %q is not a single label", line, record[0])
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("line %d: duplicate label %s", line, label)
+		}
+		seen[label] = true
+		row := &subdomainRow{line: line, label: label}
+		if row.owner, err = resolveAddress(strings.TrimSpace(record[1])); err != nil {
+			return nil, fmt.Errorf("line %d: invalid owner: %v", line, err)
+		}
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			address, err := resolveAddress(strings.TrimSpace(record[2]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid address: %v", line, err)
+			}
+			row.address = &address
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// subdomainCreateBatch creates the subdomains of a domain listed in a file
+func subdomainCreateBatch(domain string) {
+	assert(subdomainCreateOwnerStr == "" && subdomainCreateAddressStr == "", exitBadInput, "--owner and --address cannot be used with --from-file; give them in the file")
+	assert(subdomainCreateExpiryStr == "" && len(subdomainCreateFuses) == 0, exitBadInput, "--expiry and --fuses cannot be used with --from-file")
+	rows, err := readSubdomainRows(subdomainCreateFromFile)
+	errCheck(err, quiet, "Failed to read subdomains")
+	assert(len(rows) > 0, exitBadInput, "No subdomains to create")
+
+	// The subdomains are created by the owner of the domain, or of the wrapped domain
+	creator := &subdomainCreator{domain: domain}
+	creator.owner, err = ensClient.Owner(commandCtx, domain)
+	errCheck(err, quiet, "Cannot obtain owner")
+	assert(creator.owner != ens.UnknownAddress, exitNotFound, "Owner of the domain is not set")
+	if creator.owner == ensClient.NameWrapper() {
+		parent, err := ensClient.WrappedData(commandCtx, domain)
+		errCheck(err, quiet, "Failed to obtain wrapped domain")
+		assert(parent.Owner != ens.UnknownAddress, exitNotFound, "Wrapped domain has no owner; it might have expired")
+		creator.wrapped = true
+		creator.owner = parent.Owner
+		creator.expiry = parent.Expiry
+	}
+
+	resolver := ens.UnknownAddress
+	for _, row := range rows {
+		if row.address != nil {
+			if subdomainCreateResolverStr != "" {
+				resolver, err = resolveAddress(subdomainCreateResolverStr)
+				errCheck(badInput(err), quiet, "Invalid resolver address")
+			} else {
+				resolver, err = publicResolverAddress()
+				errCheck(err, quiet, "No public resolver for that network")
+			}
+			break
+		}
+	}
+
+	wallet, account, err := obtainWalletAndAccount(creator.owner, passphrase)
+	errCheck(err, quiet, "Failed to obtain account details for the owner of the domain")
+	gasPrice, err := gasPriceFlag()
+	errCheck(badInput(err), quiet, "Invalid gas price")
+	creator.opts = generateTransactOpts(&wallet, account, passphrase, gasPrice)
+	cli.Assert(confirmBatch(fmt.Sprintf("Create %d subdomains of %s", len(rows), domain)), quiet, "Transactions not confirmed")
+
+	results := make([]*subdomainBatchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, creator.create(row, resolver))
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		if quiet {
+			continue
+		}
+		if jsonOutput {
+			outputJSON(result)
+		} else if result.Error != "" {
+			fmt.Printf("%s: failed: %s\n", result.Name, result.Error)
+		} else if result.TransactionID != "" {
+			fmt.Printf("%s: created by transaction %s\n", result.Name, result.TransactionID)
+		}
+	}
+	if !quiet && !jsonOutput {
+		fmt.Printf("Created %d of %d subdomains\n", len(results)-failed, len(results))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// create creates a single subdomain.  A subdomain with an address is created
+// owned by the owner of the domain so that the address can be set, and then
+// passed to its owner
+func (c *subdomainCreator) create(row *subdomainRow, resolver common.Address) *subdomainBatchResult {
+	name := row.label + "." + c.domain
+	result := &subdomainBatchResult{Name: name, Owner: row.owner.Hex()}
+	if row.address == nil {
+		tx, err := c.createSubname(row.label, row.owner, ens.UnknownAddress)
+		if err == nil {
+			err = submitChainedTransaction(c.opts, tx, log.Fields{"name": name, "owner": row.owner.Hex()}, "Subdomain create")
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.TransactionID = tx.Hash().Hex()
+		}
+		return result
+	}
+
+	// Later transactions cannot be estimated until the subdomain exists
+	result.Address = row.address.Hex()
+	tx, err := c.createSubname(row.label, c.owner, resolver)
+	if err == nil {
+		err = submitPrerequisiteTransaction(c.opts, tx, log.Fields{"name": name, "owner": c.owner.Hex(), "resolver": resolver.Hex()}, "Subdomain create")
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.TransactionID = tx.Hash().Hex()
+	if !sendsTransactions() {
+		if !quiet {
+			fmt.Println("Further transactions for", name, "depend on this transaction and are not shown")
+		}
+		return result
+	}
+
+	tx, err = ensClient.SetAddress(commandCtx, c.opts, name, *row.address)
+	if err == nil {
+		err = submitChainedTransaction(c.opts, tx, log.Fields{"name": name, "address": row.address.Hex()}, "Address set")
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.TransactionID = tx.Hash().Hex()
+	if row.owner == c.owner {
+		return result
+	}
+
+	tx, err = c.createSubname(row.label, row.owner, ens.UnknownAddress)
+	if err == nil {
+		err = submitChainedTransaction(c.opts, tx, log.Fields{"name": name, "owner": row.owner.Hex()}, "Subdomain owner")
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.TransactionID = tx.Hash().Hex()
+	}
+	return result
+}
+
+// createSubname creates a transaction creating a subdomain through the
+// registry or the NameWrapper, setting its resolver if it is not zero
+func (c *subdomainCreator) createSubname(label string, owner common.Address, resolver common.Address) (*types.Transaction, error) {
+	if c.wrapped {
+		return ensClient.CreateWrappedSubname(commandCtx, c.opts, c.domain, label, owner, resolver, 0, c.expiry)
+	}
+	return ensClient.CreateSubname(commandCtx, c.opts, c.domain, label, owner, resolver)
+}
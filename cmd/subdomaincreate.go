@@ -0,0 +1,249 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ensclient "github.com/orinocopay/ens/client"
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var subdomainCreateOwnerStr string
+var subdomainCreateResolverStr string
+var subdomainCreateAddressStr string
+var subdomainCreateExpiryStr string
+var subdomainCreateFuses []string
+
+// subdomainCreateCmd represents the subdomain create command
+var subdomainCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an ENS subdomain",
+	Long: `Create a subdomain of a name with the Ethereum Name Service (ENS).  For example:
+
+    ens subdomain create --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase" sub.enstest.eth
+
+If the owner is not supplied then the subdomain is owned by the owner of the domain.
+
+A resolver and address for the subdomain can be set at the same time with --resolver and --address; if only --address is supplied then the public resolver is used.  This requires multiple transactions, each of which is waited for before the next is sent.
+
+If the domain is wrapped with the NameWrapper then the subdomain is created wrapped, in a single transaction, by the owner of the wrapped domain.  Fuses can be burned on a wrapped subdomain when it is created with --fuses, and last until its expiry; this is set with --expiry as a period from now (e.g. 1y or 90d) and defaults to the expiry of the domain, which it cannot exceed.  For example:
+
+    ens subdomain create --fuses=PARENT_CANNOT_CONTROL,CANNOT_UNWRAP --expiry=1y --passphrase="my secret passphrase" sub.enstest.eth
+
+Burning PARENT_CANNOT_CONTROL emancipates the subdomain, so that the owner of the domain can no longer replace or remove it; this requires CANNOT_UNWRAP to be burned on the domain.  Only the owner of a wrapped subdomain can set its address, so --address can only be used if the subdomain is owned by the owner of the domain.
+
+A number of subdomains can be created at once with --from-file, giving the domain rather than the subdomain as the argument.  Each line of the file is of the form label,owner[,address]; lines starting with # are ignored.  For example:
+
+    ens subdomain create --from-file=subdomains.csv --passphrase="my secret passphrase" enstest.eth
+
+The transactions are sent one after the other from the owner of the domain, which is asked to confirm them once, and the result for each subdomain is reported.  A subdomain that fails does not stop the others from being created.  Subdomains with an address are created in several transactions, each waited for before the next is sent.  Subdomains of a wrapped domain are given the expiry of the domain.
+
+The keystore for the owner of the domain must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transactions to create the subdomains are sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if subdomainCreateFromFile != "" {
+			subdomainCreateBatch(args[0])
+			return
+		}
+
+		// Break the name in to domain and subdomain
+		nameBits := strings.Split(args[0], ".")
+		assert(len(nameBits) >= 3, exitBadInput, "Invalid name")
+		subdomain := nameBits[0]
+		domain := args[0][len(subdomain)+1:]
+
+		// Fetch the owner of the domain
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(domain))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner of the domain is not set")
+		if owner == ensClient.NameWrapper() {
+			subdomainCreateWrapped(args[0], domain, subdomain)
+			return
+		}
+		assert(subdomainCreateExpiryStr == "" && len(subdomainCreateFuses) == 0, exitBadInput, "--expiry and --fuses can only be used if the domain is wrapped")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the domain")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		// Obtain the address who will own the subdomain
+		subdomainOwner := owner
+		if subdomainCreateOwnerStr != "" {
+			subdomainOwner, err = resolveAddress(subdomainCreateOwnerStr)
+			errCheck(badInput(err), quiet, "Invalid owner")
+		}
+
+		session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
+		configureTransactOpts(&session.TransactOpts)
+
+		if subdomainCreateResolverStr == "" && subdomainCreateAddressStr == "" {
+			tx, err := ens.SetSubdomainOwner(session, domain, subdomain, &subdomainOwner)
+			errCheck(err, quiet, "Failed to send transaction")
+			handleTransaction(tx, log.Fields{"name": args[0],
+				"owner": subdomainOwner.Hex()}, "Subdomain create")
+			return
+		}
+
+		// The domain owner must own the subdomain while its records are set up
+		tx, err := ens.SetSubdomainOwner(session, domain, subdomain, &owner)
+		errCheck(err, quiet, "Failed to send transaction")
+		if !subdomainCreateStep(&session.TransactOpts, tx, log.Fields{"name": args[0],
+			"owner": owner.Hex()}, "Subdomain create") {
+			return
+		}
+
+		var resolverAddress common.Address
+		if subdomainCreateResolverStr == "" {
+			resolverAddress, err = publicResolverAddress()
+			errCheck(err, quiet, "No public resolver for that network")
+		} else {
+			resolverAddress, err = resolveAddress(subdomainCreateResolverStr)
+			errCheck(badInput(err), quiet, "Invalid resolver address")
+		}
+		configureTransactOpts(&session.TransactOpts)
+		tx, err = ens.SetResolver(session, args[0], &resolverAddress)
+		errCheck(err, quiet, "Failed to send transaction")
+		last := subdomainCreateAddressStr == "" && subdomainOwner == owner
+		if !subdomainCreateStep(&session.TransactOpts, tx, log.Fields{"name": args[0],
+			"resolver": resolverAddress.Hex()}, "Resolver set") || last {
+			return
+		}
+
+		if subdomainCreateAddressStr != "" {
+			resolutionAddress, err := resolveAddress(subdomainCreateAddressStr)
+			errCheck(badInput(err), quiet, "Invalid address")
+			resolverContract, err := ens.ResolverContractByAddress(client, resolverAddress)
+			errCheck(err, quiet, "Failed to obtain resolver contract")
+			resolverSession := ens.CreateResolverSession(chainID, &wallet, account, passphrase, resolverContract, gasPrice)
+			configureTransactOpts(&resolverSession.TransactOpts)
+			tx, err = ens.SetResolution(resolverSession, args[0], &resolutionAddress)
+			errCheck(err, quiet, "Failed to set resolution for that name")
+			if !subdomainCreateStep(&resolverSession.TransactOpts, tx, log.Fields{"name": args[0],
+				"address": resolutionAddress.Hex()}, "Address set") || subdomainOwner == owner {
+				return
+			}
+		}
+
+		// Hand the subdomain over to its final owner
+		configureTransactOpts(&session.TransactOpts)
+		tx, err = ens.SetSubdomainOwner(session, domain, subdomain, &subdomainOwner)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner": subdomainOwner.Hex()}, "Subdomain owner")
+	},
+}
+
+// subdomainCreateWrapped creates a subdomain of a wrapped domain through the
+// NameWrapper
+func subdomainCreateWrapped(name string, domain string, subdomain string) {
+	parent, err := ensClient.WrappedData(commandCtx, domain)
+	errCheck(err, quiet, "Failed to obtain wrapped domain")
+	assert(parent.Owner != ens.UnknownAddress, exitNotFound, "Wrapped domain has no owner; it might have expired")
+
+	fuses, err := ensclient.ParseFuses(subdomainCreateFuses)
+	errCheck(badInput(err), quiet, "Invalid fuses")
+	errCheck(ensclient.CheckSubnameFuses(parent.Fuses, fuses), quiet, "Fuses cannot be burned")
+	expiry := parent.Expiry
+	if subdomainCreateExpiryStr != "" {
+		period, err := parseRegistrationDuration(subdomainCreateExpiryStr)
+		errCheck(badInput(err), quiet, "Invalid expiry")
+		expiry = time.Now().Add(period)
+		assert(!expiry.After(parent.Expiry), exitBadInput, fmt.Sprintf("Expiry cannot be later than that of the domain (%s)", parent.Expiry.UTC().Format(time.RFC1123)))
+	}
+
+	subdomainOwner := parent.Owner
+	if subdomainCreateOwnerStr != "" {
+		subdomainOwner, err = resolveAddress(subdomainCreateOwnerStr)
+		errCheck(badInput(err), quiet, "Invalid owner")
+	}
+	assert(subdomainCreateAddressStr == "" || subdomainOwner == parent.Owner, exitBadInput, "--address cannot be used if the wrapped subdomain is owned by someone other than the owner of the domain; set the address afterwards as the owner of the subdomain")
+
+	var resolverAddress common.Address
+	if subdomainCreateResolverStr != "" {
+		resolverAddress, err = resolveAddress(subdomainCreateResolverStr)
+		errCheck(badInput(err), quiet, "Invalid resolver address")
+	} else if subdomainCreateAddressStr != "" {
+		resolverAddress, err = publicResolverAddress()
+		errCheck(err, quiet, "No public resolver for that network")
+	}
+
+	wallet, account, err := obtainWalletAndAccount(parent.Owner, passphrase)
+	errCheck(err, quiet, "Failed to obtain account details for the owner of the wrapped domain")
+	gasPrice, err := gasPriceFlag()
+	errCheck(badInput(err), quiet, "Invalid gas price")
+	opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+	tx, err := ensClient.CreateWrappedSubname(commandCtx, opts, domain, subdomain, subdomainOwner, resolverAddress, fuses, expiry)
+	errCheck(err, quiet, "Failed to send transaction")
+	fields := log.Fields{"name": name,
+		"owner":  subdomainOwner.Hex(),
+		"fuses":  fuses,
+		"expiry": expiry.Unix()}
+	if subdomainCreateAddressStr == "" {
+		handleTransaction(tx, fields, "Wrapped subdomain create")
+		return
+	}
+	if !subdomainCreateStep(opts, tx, fields, "Wrapped subdomain create") {
+		return
+	}
+
+	resolutionAddress, err := resolveAddress(subdomainCreateAddressStr)
+	errCheck(badInput(err), quiet, "Invalid address")
+	opts = generateTransactOpts(&wallet, account, passphrase, gasPrice)
+	tx, err = ensClient.SetAddress(commandCtx, opts, name, resolutionAddress)
+	errCheck(err, quiet, "Failed to set resolution for that name")
+	handleTransaction(tx, log.Fields{"name": name,
+		"address": resolutionAddress.Hex()}, "Address set")
+}
+
+// subdomainCreateStep sends a transaction that later transactions depend
+// on and waits for it to be mined.  It returns false if no further
+// transactions should be created
+func subdomainCreateStep(opts *bind.TransactOpts, tx *types.Transaction, fields log.Fields, msg string) bool {
+	if err := submitPrerequisiteTransaction(opts, tx, fields, msg); err != nil {
+		reportTransactionError(err)
+	}
+	if !sendsTransactions() {
+		if !quiet {
+			fmt.Println("Further transactions depend on this transaction and are not shown")
+		}
+		return false
+	}
+	return true
+}
+
+func init() {
+	subdomainCmd.AddCommand(subdomainCreateCmd)
+
+	subdomainCreateCmd.Flags().StringVarP(&subdomainCreateOwnerStr, "owner", "o", "", "Owner of the subdomain (defaults to the owner of the domain)")
+	subdomainCreateCmd.Flags().StringVarP(&subdomainCreateResolverStr, "resolver", "r", "", "Resolver for the subdomain")
+	subdomainCreateCmd.Flags().StringVarP(&subdomainCreateAddressStr, "address", "a", "", "Address for the subdomain")
+	subdomainCreateCmd.Flags().StringVar(&subdomainCreateExpiryStr, "expiry", "", "Period from now until a wrapped subdomain expires, e.g. 1y (defaults to the expiry of the domain)")
+	subdomainCreateCmd.Flags().StringSliceVar(&subdomainCreateFuses, "fuses", nil, "Comma-separated fuses to burn on a wrapped subdomain")
+	subdomainCreateCmd.Flags().StringVar(&subdomainCreateFromFile, "from-file", "", "File of subdomains to create, one label,owner[,address] per line")
+	addTransactionFlags(subdomainCreateCmd, "Passphrase for the account that owns the domain")
+}
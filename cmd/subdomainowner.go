@@ -15,21 +15,15 @@ package cmd
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 
-	etherutils "github.com/orinocopay/go-etherutils"
 	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
-var subdomainOwnerPassphrase string
 var subdomainOwnerNameStr string
-var subdomainOwnerGasPriceStr string
-var subdomainOwnerBidPriceStr string
-var subdomainOwnerMaskPriceStr string
 
 // subdomainOwnerCmd represents the subdomainOwner set command
 var subdomainOwnerCmd = &cobra.Command{
@@ -41,56 +35,45 @@ var subdomainOwnerCmd = &cobra.Command{
 
 The keystore for the owner of the domain must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to set the owner of the subdomain is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to set the owner of the subdomain is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Break the name in to domain and subdomain
 		nameBits := strings.Split(args[0], ".")
-		cli.Assert(len(nameBits) >= 3, quiet, "Invalid name")
+		assert(len(nameBits) >= 3, exitBadInput, "Invalid name")
 		subdomain := nameBits[0]
 		domain := args[0][len(subdomain)+1:]
 
 		// Ensure that the name is in a suitable state
-		registrarContract, err := ens.RegistrarContract(client)
-		inState, err := ens.NameInState(registrarContract, client, domain, "Owned")
-		cli.ErrAssert(inState, err, quiet, "Name not in a suitable state to set a subdomain owner")
-
-		// Obtain the registry contract
-		registryContract, err := ens.RegistryContract(client)
-		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+		state, err := ensClient.AuctionState(commandCtx, domain)
+		cli.ErrAssert(state == "Owned", err, quiet, "Name not in a suitable state to set a subdomain owner")
 
 		// Fetch the owner of the domain
-		cli.ErrCheck(err, quiet, "Invalid name")
-		owner, err := registryContract.Owner(nil, ens.NameHash(domain))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		errCheck(badInput(err), quiet, "Invalid name")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(domain))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
 
 		// Fetch the wallet and account for the owner
-		wallet, err := cli.ObtainWallet(chainID, owner)
-		cli.ErrCheck(err, quiet, "Failed to obtain a wallet for the owner")
-		account, err := cli.ObtainAccount(&wallet, &owner, subdomainOwnerPassphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain an account for the owner")
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the owner")
 
-		gasPrice, err := etherutils.StringToWei(subdomainOwnerGasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
 		// Obtain the address who will own the subdomain
-		subdomainOwnerAddress, err := ens.Resolve(client, subdomainOwnerNameStr)
-		cli.ErrCheck(err, quiet, "Invalid owner")
+		subdomainOwnerAddress, err := resolveAddress(subdomainOwnerNameStr)
+		errCheck(badInput(err), quiet, "Invalid owner")
 
 		// Set up our session
-		session := ens.CreateRegistrySession(chainID, &wallet, account, subdomainOwnerPassphrase, registryContract, gasPrice)
+		session := ens.CreateRegistrySession(chainID, &wallet, account, passphrase, registryContract, gasPrice)
+		configureTransactOpts(&session.TransactOpts)
 
 		// Set the subdomain owner
 		tx, err := ens.SetSubdomainOwner(session, domain, subdomain, &subdomainOwnerAddress)
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"networkid": chainID,
-			"name":      args[0],
-			"owner":     subdomainOwnerAddress.Hex()}).Info("Subdomain owner")
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"owner": subdomainOwnerAddress.Hex()}, "Subdomain owner")
 
 	},
 }
@@ -98,7 +81,6 @@ In quiet mode this will return 0 if the transaction to set the owner of the subd
 func init() {
 	subdomainCmd.AddCommand(subdomainOwnerCmd)
 
-	subdomainOwnerCmd.Flags().StringVarP(&subdomainOwnerPassphrase, "passphrase", "p", "", "Passphrase for the account that owns the name")
 	subdomainOwnerCmd.Flags().StringVarP(&subdomainOwnerNameStr, "owner", "o", "", "Owner of the subdomain")
-	subdomainOwnerCmd.Flags().StringVarP(&subdomainOwnerGasPriceStr, "gasprice", "g", "4 GWei", "Gas price for the transaction")
+	addTransactionFlags(subdomainOwnerCmd, "Passphrase for the account that owns the name")
 }
@@ -0,0 +1,98 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var textKey string
+
+// standardTextKeys are the text record keys defined by EIP-634 and in common use
+var standardTextKeys = []string{
+	"email",
+	"url",
+	"avatar",
+	"description",
+	"notice",
+	"keywords",
+	"com.discord",
+	"com.github",
+	"com.reddit",
+	"com.twitter",
+	"org.telegram",
+}
+
+type textResult struct {
+	Name     string            `json:"name"`
+	NameHash string            `json:"namehash"`
+	Records  map[string]string `json:"records"`
+}
+
+// textCmd represents the text command
+var textCmd = &cobra.Command{
+	Use:   "text",
+	Short: "Obtain text records associated with an ENS name",
+	Long: `Obtain text records (EIP-634) associated with a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens text --key=url enstest.eth
+
+The key can also be supplied as the second argument.  If no key is supplied then all standard keys that are set will be shown.
+
+In quiet mode this will return 0 if the name has the requested text record, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if textKey == "" && len(args) > 1 {
+			textKey = args[1]
+		}
+
+		keys := standardTextKeys
+		if textKey != "" {
+			keys = []string{textKey}
+		}
+		records := make(map[string]string)
+		for _, key := range keys {
+			value, err := ensClient.Text(commandCtx, args[0], key)
+			errCheck(err, quiet, fmt.Sprintf("Failed to obtain text record %s", key))
+			if value != "" {
+				records[key] = value
+			}
+		}
+		assert(len(records) > 0, exitNotFound, "No text records for that name")
+		if !quiet {
+			if jsonOutput {
+				outputJSON(&textResult{
+					Name:     args[0],
+					NameHash: nameHashHex(args[0]),
+					Records:  records,
+				})
+			} else if textKey != "" {
+				fmt.Println(records[textKey])
+			} else {
+				for _, key := range keys {
+					if value, exists := records[key]; exists {
+						fmt.Printf("%s: %s\n", key, value)
+					}
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(textCmd)
+
+	textCmd.Flags().StringVarP(&textKey, "key", "k", "", "Key of the text record")
+}
@@ -0,0 +1,97 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	"github.com/spf13/cobra"
+)
+
+var textClearKeys []string
+var textClearAll bool
+
+// textClearCmd represents the text clear command
+var textClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove text records of an ENS name",
+	Long: `Remove a number of text records (EIP-634) of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens text clear --keys=url,com.twitter --passphrase="my secret passphrase" enstest.eth
+
+With --all the standard keys are removed instead, being email, url, avatar, description, notice, keywords, com.discord, com.github, com.reddit, com.twitter and org.telegram.  Only records that are set are removed, and the number removed is shown.  If the resolver supports multicall the records are removed in a single transaction, otherwise a transaction is sent for each record.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transactions to remove the records are sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(len(textClearKeys) > 0 || textClearAll, exitBadInput, "Keys or --all are required")
+		assert(len(textClearKeys) == 0 || !textClearAll, exitBadInput, "Keys cannot be used with --all")
+		keys := textClearKeys
+		if textClearAll {
+			keys = standardTextKeys
+		}
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Ensure that the resolver holds text records
+		resolverAddress, err := ensClient.Resolver(commandCtx, args[0])
+		errCheck(err, quiet, "No resolver for that name")
+		assert(resolverAddress != ens.UnknownAddress, exitNotFound, "No resolver for that name")
+		err = checkResolverInterface(resolverAddress, "text")
+		errCheck(err, quiet, "Resolver cannot hold text records")
+
+		// Only clear the records that are set
+		node := ens.NameHash(args[0])
+		calls := make([]*resolverRecordCall, 0)
+		for _, key := range keys {
+			value, err := ensClient.Text(commandCtx, args[0], key)
+			errCheck(err, quiet, fmt.Sprintf("Failed to obtain text record %s", key))
+			if value != "" {
+				calls = append(calls, &resolverRecordCall{
+					description: fmt.Sprintf("text record %s", key),
+					method:      "setText",
+					params:      []interface{}{node, key, ""},
+				})
+			}
+		}
+		assert(len(calls) > 0, exitNotFound, "None of the text records are set")
+		if !quiet {
+			fmt.Printf("Removing %d of %d text records\n", len(calls), len(keys))
+		}
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+
+		sendResolverRecordCalls(resolverAddress, opts, args[0], calls, "Removing", "Text clear")
+	},
+}
+
+func init() {
+	textCmd.AddCommand(textClearCmd)
+
+	textClearCmd.Flags().StringSliceVar(&textClearKeys, "keys", nil, "Comma-separated keys of the text records to remove")
+	textClearCmd.Flags().BoolVar(&textClearAll, "all", false, "Remove all standard text records")
+	addTransactionFlags(textClearCmd, "Passphrase for the account that owns the name")
+}
@@ -0,0 +1,84 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/orinocopay/go-etherutils/ens"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var textSetKey string
+var textSetValue string
+
+// textSetCmd represents the text set command
+var textSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a text record of an ENS name",
+	Long: `Set a text record (EIP-634) of a name registered with the Ethereum Name Service (ENS).  For example:
+
+    ens text set --key=url --value=https://www.example.com/ --passphrase="my secret passphrase" enstest.eth
+
+Standard keys include email, url, avatar, description, notice, keywords, com.github and com.twitter, but any key can be used.  Setting an empty value removes the record; to remove a number of records see 'ens text clear'.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+
+In quiet mode this will return 0 if the transaction to set the text record is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		assert(textSetKey != "", exitBadInput, "Key is required")
+		assert(cmd.Flags().Changed("value"), exitBadInput, "Value is required (use an empty value to remove the record)")
+
+		// Ensure that the name is in a suitable state
+		assert(inState(args[0], "Owned"), exitNotFound, "Domain not in a suitable state to set a text record")
+
+		// Fetch the owner of the name
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
+
+		// Fetch the wallet and account for the owner
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain account details for the owner of the name")
+
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
+
+		// Obtain the resolver for this name
+		resolverAddress, err := ens.Resolver(registryContract, args[0])
+		errCheck(err, quiet, "No resolver for that name")
+		err = checkResolverInterface(resolverAddress, "text")
+		errCheck(err, quiet, "Resolver cannot hold text records")
+
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
+		tx, err := ensClient.SetText(commandCtx, opts, args[0], textSetKey, textSetValue)
+		errCheck(err, quiet, "Failed to set text record for that name")
+		if !quiet && textSetValue == "" {
+			fmt.Println("Removing text record", textSetKey)
+		}
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"key":   textSetKey,
+			"value": textSetValue}, "Text set")
+	},
+}
+
+func init() {
+	textCmd.AddCommand(textSetCmd)
+
+	textSetCmd.Flags().StringVarP(&textSetKey, "key", "k", "", "Key of the text record")
+	textSetCmd.Flags().StringVarP(&textSetValue, "value", "v", "", "Value of the text record")
+	addTransactionFlags(textSetCmd, "Passphrase for the account that owns the name")
+}
@@ -0,0 +1,62 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/spf13/cobra"
+)
+
+var timeout time.Duration
+
+// commandCtx is the context for the calls made by a command.  It expires
+// when the command has run for longer than --timeout
+var commandCtx = context.Background()
+var cancelCommand context.CancelFunc = func() {}
+
+// untimedCommands are commands that run until they are interrupted, so are
+// not subject to --timeout
+var untimedCommands = map[string]bool{
+	"ens auction watch": true,
+}
+
+// startTimeout creates the context for the calls made by a command.  Once
+// it expires calls fail with context.DeadlineExceeded, which the command
+// reports as it would any other failed call.  Any time spent waiting for a
+// transaction to be mined with --wait is allowed in addition to the timeout
+func startTimeout(cmd *cobra.Command) {
+	if timeout <= 0 || untimedCommands[cmd.CommandPath()] {
+		return
+	}
+	limit := timeout
+	if wait {
+		limit += waitTimeout
+	}
+	commandCtx, cancelCommand = context.WithTimeout(context.Background(), limit)
+}
+
+// timedOut returns true if an error is the result of the command running
+// for longer than --timeout
+func timedOut(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) && errors.Is(commandCtx.Err(), context.DeadlineExceeded)
+}
+
+// callOpts returns the options for calls to contracts made by a command
+func callOpts() *bind.CallOpts {
+	return &bind.CallOpts{Context: commandCtx, BlockNumber: readBlock}
+}
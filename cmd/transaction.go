@@ -0,0 +1,733 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	ensclient "github.com/orinocopay/ens/client"
+	etherutils "github.com/orinocopay/go-etherutils"
+	"github.com/orinocopay/go-etherutils/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+// minGasLimit is the gas used by the simplest transaction
+const minGasLimit = 21000
+
+// replaceBump is the percentage by which fees are raised to replace a pending transaction
+const replaceBump = 15
+
+var replace bool
+
+// nextNonces are the nonces that follow the transactions submitted by the
+// command, keyed by sender
+var nextNonces = make(map[common.Address]uint64)
+var skipBalanceCheck bool
+
+// feeHistoryBlocks is the number of recent blocks whose priority fees are
+// used to suggest the priority fee of a transaction
+const feeHistoryBlocks = 10
+
+var gasMultiplier float64
+
+// outputTxFrom is the sender of transactions written with --output-tx
+var outputTxFrom common.Address
+
+// outputTxWritten is true once a transaction has been written with
+// --output-tx, after which further transactions are appended
+var outputTxWritten bool
+
+// errUnsigned is returned when an unsigned wallet is asked to sign anything
+// other than a transaction
+var errUnsigned = errors.New("only transactions can be created with --output-tx")
+
+// unsignedWallet is a wallet that leaves transactions unsigned, for export
+// with --output-tx
+type unsignedWallet struct {
+	address common.Address
+}
+
+// URL returns an empty URL as the wallet has no backing store
+func (w *unsignedWallet) URL() accounts.URL {
+	return accounts.URL{}
+}
+
+// Status returns the status of the wallet, which is always open
+func (w *unsignedWallet) Status() (string, error) {
+	return "Unsigned", nil
+}
+
+// Open does nothing as the wallet is always open
+func (w *unsignedWallet) Open(passphrase string) error {
+	return nil
+}
+
+// Close does nothing as the wallet is always open
+func (w *unsignedWallet) Close() error {
+	return nil
+}
+
+// Accounts returns the account whose transactions are left unsigned
+func (w *unsignedWallet) Accounts() []accounts.Account {
+	return []accounts.Account{{Address: w.address}}
+}
+
+// Contains returns true if the account is the one whose transactions are left unsigned
+func (w *unsignedWallet) Contains(account accounts.Account) bool {
+	return account.Address == w.address
+}
+
+// Derive is not supported
+func (w *unsignedWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive does nothing as accounts cannot be derived
+func (w *unsignedWallet) SelfDerive(bases []accounts.DerivationPath, chain ethereum.ChainStateReader) {
+}
+
+// SignData is not supported
+func (w *unsignedWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	return nil, errUnsigned
+}
+
+// SignDataWithPassphrase is not supported
+func (w *unsignedWallet) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
+	return nil, errUnsigned
+}
+
+// SignText is not supported
+func (w *unsignedWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	return nil, errUnsigned
+}
+
+// SignTextWithPassphrase is not supported
+func (w *unsignedWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, errUnsigned
+}
+
+// SignTx returns the transaction unsigned
+func (w *unsignedWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return tx, nil
+}
+
+// SignTxWithPassphrase returns the transaction unsigned
+func (w *unsignedWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return tx, nil
+}
+
+// unsignedTransaction is the JSON form of a transaction written with --output-tx
+type unsignedTransaction struct {
+	From                 common.Address  `json:"from"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	To                   *common.Address `json:"to"`
+	Value                *hexutil.Big    `json:"value"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Data                 hexutil.Bytes   `json:"data"`
+}
+
+// sendsTransactions returns true if transactions are sent to the network
+// rather than printed or written to a file
+func sendsTransactions() bool {
+	return !dryRun && outputTxFile == "" && !estimateOnly
+}
+
+// signsElsewhere returns true if transactions are left unsigned, either to
+// be written with --output-tx or because they are only being estimated
+func signsElsewhere() bool {
+	return outputTxFile != "" || estimateOnly
+}
+
+// configureTransactOpts applies the common transaction command-line
+// arguments to the options of a session
+func configureTransactOpts(opts *bind.TransactOpts) {
+	opts.Context = commandCtx
+	replacing := configureNonce(opts)
+	if gasLimit != 0 {
+		assert(gasLimit >= minGasLimit, exitBadInput, fmt.Sprintf("Gas limit must be at least %d", minGasLimit))
+		// Setting the gas limit stops the gas being estimated
+		opts.GasLimit = gasLimit
+	}
+	if maxFeeStr != "" {
+		configureDynamicFees(opts)
+	} else if gasPriceStr == "" {
+		configureSuggestedFees(opts)
+	}
+	if replacing {
+		bumpFees(opts)
+	}
+	// Build and sign the transaction but do not send it; it is sent by
+	// handleTransaction once it has been reported on
+	opts.NoSend = true
+}
+
+// generateTransactOpts creates transaction options for contracts that do not
+// have their own session
+func generateTransactOpts(wallet *accounts.Wallet, account *accounts.Account, passphrase string, gasPrice *big.Int) *bind.TransactOpts {
+	opts := &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return (*wallet).SignTxWithPassphrase(*account, passphrase, tx, chainID)
+		},
+		GasPrice: gasPrice,
+	}
+	configureTransactOpts(opts)
+	return opts
+}
+
+// configureNonce sets the nonce of a transaction.  For the first
+// transaction of a command from an account this is the nonce given by
+// --nonce if supplied, the account's first unmined nonce with --replace,
+// and otherwise the account's pending nonce; later transactions follow on
+// from it.  It returns true if the transaction replaces a pending one
+func configureNonce(opts *bind.TransactOpts) bool {
+	if next, exists := nextNonces[opts.From]; exists {
+		opts.Nonce = new(big.Int).SetUint64(next)
+		return false
+	}
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	current, err := client.NonceAt(ctx, opts.From, nil)
+	errCheck(err, quiet, "Failed to obtain nonce")
+	switch {
+	case nonce != -1:
+		if uint64(nonce) < current && !quiet {
+			fmt.Fprintf(os.Stderr, "Nonce %d is below the account's current nonce %d so the transaction will be rejected\n", nonce, current)
+		}
+		opts.Nonce = big.NewInt(nonce)
+	case replace:
+		opts.Nonce = new(big.Int).SetUint64(current)
+		return true
+	default:
+		pending, err := client.PendingNonceAt(ctx, opts.From)
+		errCheck(err, quiet, "Failed to obtain pending nonce")
+		opts.Nonce = new(big.Int).SetUint64(pending)
+	}
+	return false
+}
+
+// bumpFees raises the fees of a transaction so that it replaces a pending
+// transaction with the same nonce.  Nodes require a replacement to pay at
+// least 10% more, so fees are raised by replaceBump over the higher of the
+// supplied fees and the node's suggestion
+func bumpFees(opts *bind.TransactOpts) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	bump := func(value *big.Int, suggested *big.Int) *big.Int {
+		if value == nil || (suggested != nil && suggested.Cmp(value) > 0) {
+			value = suggested
+		}
+		return new(big.Int).Div(new(big.Int).Mul(value, big.NewInt(100+replaceBump)), big.NewInt(100))
+	}
+	if opts.GasFeeCap != nil {
+		suggestedTip, err := client.SuggestGasTipCap(ctx)
+		errCheck(err, quiet, "Failed to obtain suggested priority fee")
+		opts.GasTipCap = bump(opts.GasTipCap, suggestedTip)
+		opts.GasFeeCap = bump(opts.GasFeeCap, nil)
+		if opts.GasFeeCap.Cmp(opts.GasTipCap) < 0 {
+			opts.GasFeeCap = opts.GasTipCap
+		}
+	} else {
+		suggested, err := client.SuggestGasPrice(ctx)
+		errCheck(err, quiet, "Failed to obtain suggested gas price")
+		opts.GasPrice = bump(opts.GasPrice, suggested)
+	}
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Replacing pending transaction with nonce", opts.Nonce)
+	}
+}
+
+// gasPriceFlag returns the gas price given by --gasprice, or nil if it is
+// not supplied, in which case the fees suggested by the node are used
+func gasPriceFlag() (*big.Int, error) {
+	if gasPriceStr == "" {
+		return nil, nil
+	}
+	return etherutils.StringToWei(gasPriceStr)
+}
+
+// configureSuggestedFees sets the fees of a session to those suggested by
+// the node, multiplied by --gas-multiplier.  On chains that support EIP-1559
+// the priority fee is the median paid over recent blocks, and the maximum
+// fee allows for the base fee doubling before the transaction is mined
+func configureSuggestedFees(opts *bind.TransactOpts) {
+	assert(gasMultiplier > 0, exitBadInput, "Gas multiplier must be greater than 0")
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{50})
+	if err == nil && len(history.BaseFee) > 0 && history.BaseFee[len(history.BaseFee)-1].Sign() > 0 {
+		// The final base fee is that of the next block
+		baseFee := history.BaseFee[len(history.BaseFee)-1]
+		priorityFee := medianReward(history.Reward)
+		if priorityFee == nil {
+			priorityFee, err = client.SuggestGasTipCap(ctx)
+			errCheck(err, quiet, "Failed to obtain suggested priority fee")
+		}
+		opts.GasPrice = nil
+		opts.GasTipCap = multiplyGas(priorityFee)
+		opts.GasFeeCap = new(big.Int).Add(multiplyGas(new(big.Int).Mul(baseFee, big.NewInt(2))), opts.GasTipCap)
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Using suggested maximum fee of %s with priority fee of %s\n", etherutils.WeiToString(opts.GasFeeCap, true), etherutils.WeiToString(opts.GasTipCap, true))
+		}
+		return
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	errCheck(err, quiet, "Failed to obtain suggested gas price")
+	opts.GasPrice = multiplyGas(gasPrice)
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Using suggested gas price of", etherutils.WeiToString(opts.GasPrice, true))
+	}
+}
+
+// medianReward returns the median of the priority fees paid in recent
+// blocks, or nil if there are none
+func medianReward(rewards [][]*big.Int) *big.Int {
+	fees := make([]*big.Int, 0, len(rewards))
+	for _, reward := range rewards {
+		if len(reward) > 0 && reward[0] != nil {
+			fees = append(fees, reward[0])
+		}
+	}
+	if len(fees) == 0 {
+		return nil
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i].Cmp(fees[j]) < 0 })
+	return fees[len(fees)/2]
+}
+
+// multiplyGas multiplies a fee by --gas-multiplier
+func multiplyGas(fee *big.Int) *big.Int {
+	if gasMultiplier == 1 {
+		return new(big.Int).Set(fee)
+	}
+	result, _ := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(gasMultiplier)).Int(nil)
+	return result
+}
+
+// configureDynamicFees sets up the options of a session to create an
+// EIP-1559 transaction, if supported by the chain
+func configureDynamicFees(opts *bind.TransactOpts) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, nil)
+	errCheck(err, quiet, "Failed to obtain latest block")
+	if header.BaseFee == nil {
+		// Chain does not support EIP-1559 so stay with the legacy gas price
+		if !quiet {
+			fmt.Fprintln(os.Stderr, "Chain does not support EIP-1559 fees; using gas price")
+		}
+		return
+	}
+
+	maxFee, err := etherutils.StringToWei(maxFeeStr)
+	errCheck(badInput(err), quiet, "Invalid maximum fee")
+	var priorityFee *big.Int
+	if priorityFeeStr == "" {
+		priorityFee, err = client.SuggestGasTipCap(ctx)
+		errCheck(err, quiet, "Failed to obtain suggested priority fee")
+	} else {
+		priorityFee, err = etherutils.StringToWei(priorityFeeStr)
+		errCheck(badInput(err), quiet, "Invalid priority fee")
+	}
+	assert(priorityFee.Cmp(maxFee) <= 0, exitBadInput, "Priority fee cannot be higher than maximum fee")
+
+	opts.GasPrice = nil
+	opts.GasFeeCap = maxFee
+	opts.GasTipCap = priorityFee
+}
+
+// transactionError is a failure to handle a transaction, along with the
+// message with which it is reported
+type transactionError struct {
+	msg string
+	err error
+}
+
+func (e *transactionError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return fmt.Sprintf("%s: %v", e.msg, e.err)
+}
+
+// waitTimeoutError is a transaction that was not mined before --wait-timeout
+type waitTimeoutError struct {
+	tx *types.Transaction
+}
+
+func (e *waitTimeoutError) Error() string {
+	return fmt.Sprintf("Transaction %s not mined within %v", e.tx.Hash().Hex(), waitTimeout)
+}
+
+// revertedError is a transaction that was mined but reverted
+type revertedError struct {
+	block  *big.Int
+	reason string
+}
+
+func (e *revertedError) Error() string {
+	if e.reason == "" {
+		return fmt.Sprintf("Transaction mined in block %v but was reverted", e.block)
+	}
+	return fmt.Sprintf("Transaction mined in block %v but was reverted: %s", e.block, e.reason)
+}
+
+// reportTransactionError reports a failure to handle a transaction and exits
+func reportTransactionError(err error) {
+	switch err.(type) {
+	case *waitTimeoutError:
+		if !quiet {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitWaitTimeout)
+	case *revertedError:
+		if !quiet {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitRevert)
+	}
+	txErr, isTxErr := err.(*transactionError)
+	if !isTxErr {
+		errCheck(err, quiet, "Failed to send transaction")
+	}
+	if txErr.err == nil {
+		cli.Err(quiet, txErr.msg)
+	}
+	errCheck(txErr.err, quiet, txErr.msg)
+}
+
+// handleTransaction estimates the cost of, sends and reports on a
+// transaction created by a command
+func handleTransaction(tx *types.Transaction, fields log.Fields, msg string) {
+	if err := submitTransaction(tx, fields, msg); err != nil {
+		reportTransactionError(err)
+	}
+	if wait && sendsTransactions() {
+		waitForTransaction(tx)
+	}
+}
+
+// submitTransaction estimates the cost of, sends and reports on a
+// transaction, returning a *transactionError rather than exiting if this
+// fails.  It does not wait for the transaction to be mined
+func submitTransaction(tx *types.Transaction, fields log.Fields, msg string) error {
+	callMsg, err := transactionCallMsg(tx)
+	if err != nil {
+		return &transactionError{"Failed to obtain transaction sender", err}
+	}
+	gas := tx.Gas()
+	if gasLimit != 0 {
+		reportFee("Gas limit:", gas, callMsg)
+	} else if gas, err = estimateAndReport(client, callMsg); err != nil {
+		return &transactionError{"Failed to estimate gas for transaction", err}
+	}
+	if estimateOnly {
+		return nil
+	}
+	if err = checkBalance(callMsg, gas); err != nil {
+		return &transactionError{"Balance check failed", err}
+	}
+	// Later transactions of the command follow on from this one, whether it
+	// is sent, printed or written
+	if dryRun {
+		if !quiet {
+			printTransaction(tx)
+		}
+		nextNonces[callMsg.From] = tx.Nonce() + 1
+		return nil
+	}
+	if outputTxFile != "" {
+		if err = writeUnsignedTransaction(tx); err != nil {
+			return &transactionError{"Failed to write transaction", err}
+		}
+		if !quiet {
+			fmt.Println("Unsigned transaction written to", outputTxFile)
+		}
+		nextNonces[callMsg.From] = tx.Nonce() + 1
+		fields["networkid"] = chainID
+		log.WithFields(fields).Info(msg + " (unsigned)")
+		return nil
+	}
+	if !confirmTransaction(tx, gas, fields, msg) {
+		return &transactionError{"Transaction not confirmed", nil}
+	}
+	if err = sendTransaction(tx); err != nil {
+		return &transactionError{"Failed to send transaction", err}
+	}
+	nextNonces[callMsg.From] = tx.Nonce() + 1
+	if !quiet {
+		fmt.Println("Transaction ID is", tx.Hash().Hex())
+	}
+	fields["transactionid"] = tx.Hash().Hex()
+	fields["networkid"] = chainID
+	log.WithFields(fields).Info(msg)
+	return nil
+}
+
+// submitChainedTransaction submits one of a number of transactions created
+// with the same options, moving them on to the next nonce only if the
+// transaction was submitted so that a failure does not leave a gap in the
+// account's nonces.  It waits for the transaction to be mined with --wait
+func submitChainedTransaction(opts *bind.TransactOpts, tx *types.Transaction, fields log.Fields, msg string) error {
+	if err := submitTransaction(tx, fields, msg); err != nil {
+		return err
+	}
+	opts.Nonce = new(big.Int).SetUint64(tx.Nonce() + 1)
+	if wait && sendsTransactions() {
+		return awaitTransaction(tx)
+	}
+	return nil
+}
+
+// submitPrerequisiteTransaction submits a transaction as
+// submitChainedTransaction does, but always waits for a sent transaction to
+// be mined as those that follow it cannot be estimated until it is
+func submitPrerequisiteTransaction(opts *bind.TransactOpts, tx *types.Transaction, fields log.Fields, msg string) error {
+	if err := submitChainedTransaction(opts, tx, fields, msg); err != nil {
+		return err
+	}
+	if !wait && sendsTransactions() {
+		return awaitTransaction(tx)
+	}
+	return nil
+}
+
+// waitForTransaction waits for a transaction to be mined and reports its
+// status, exiting with 6 if the transaction was reverted or 2 if it was not
+// mined in time
+func waitForTransaction(tx *types.Transaction) {
+	if err := awaitTransaction(tx); err != nil {
+		reportTransactionError(err)
+	}
+}
+
+// awaitTransaction waits for a transaction to be mined and reports its
+// status, returning a *revertedError if the transaction was reverted or a
+// *waitTimeoutError if it was not mined in time
+func awaitTransaction(tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(commandCtx, waitTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &waitTimeoutError{tx}
+		}
+		return &transactionError{"Failed to wait for transaction to be mined", err}
+	}
+	// Registry lookups made after this must see the transaction's changes
+	ensClient.ClearCache()
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		if !quiet {
+			fmt.Println("Transaction mined in block", receipt.BlockNumber, "and succeeded")
+		}
+		return nil
+	}
+	return &revertedError{receipt.BlockNumber, transactionRevertReason(tx, receipt.BlockNumber)}
+}
+
+// sendTransaction sends a signed transaction to the network
+func sendTransaction(tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	return client.SendTransaction(ctx, tx)
+}
+
+// transactionCallMsg creates a call message equivalent to a signed transaction
+func transactionCallMsg(tx *types.Transaction) (ethereum.CallMsg, error) {
+	from := outputTxFrom
+	if !signsElsewhere() {
+		var err error
+		from, err = types.Sender(types.LatestSignerForChainID(chainID), tx)
+		if err != nil {
+			return ethereum.CallMsg{}, err
+		}
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	} else {
+		msg.GasPrice = tx.GasPrice()
+	}
+	return msg, nil
+}
+
+// estimateAndReport estimates the gas used by a transaction and, unless in
+// quiet mode, prints the estimate and the resultant fee
+func estimateAndReport(client *ethclient.Client, msg ethereum.CallMsg) (gas uint64, err error) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	gas, err = client.EstimateGas(ctx, msg)
+	if err != nil {
+		err = ensclient.WithRevertReason(err)
+		return
+	}
+	reportFee("Estimated gas:", gas, msg)
+	return
+}
+
+// checkBalance checks that the sender of a transaction that carries value can
+// pay the value and the maximum fee for the gas, unless --skip-balance-check
+// is supplied
+func checkBalance(msg ethereum.CallMsg, gas uint64) error {
+	if skipBalanceCheck || msg.Value == nil || msg.Value.Sign() == 0 {
+		return nil
+	}
+	feePerGas := msg.GasPrice
+	if msg.GasFeeCap != nil {
+		feePerGas = msg.GasFeeCap
+	}
+	fee := new(big.Int)
+	if feePerGas != nil {
+		fee.Mul(new(big.Int).SetUint64(gas), feePerGas)
+	}
+	balance, err := accountBalance(msg.From)
+	if err != nil {
+		return err
+	}
+	return checkFunds(msg.From, balance, msg.Value, fee)
+}
+
+// checkValueBalance checks that the account of a transaction's options can
+// pay the value to be sent with it.  This is called before the transaction
+// is built, as building it estimates its gas and the node fails to estimate
+// a transaction whose value cannot be paid with no indication of how much
+// is missing
+func checkValueBalance(opts *bind.TransactOpts, value *big.Int) error {
+	if skipBalanceCheck || estimateOnly || value == nil || value.Sign() == 0 {
+		return nil
+	}
+	balance, err := accountBalance(opts.From)
+	if err != nil {
+		return err
+	}
+	return checkFunds(opts.From, balance, value, new(big.Int))
+}
+
+// accountBalance obtains the balance of an account
+func accountBalance(address common.Address) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(commandCtx, 30*time.Second)
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain balance of %s: %v", address.Hex(), err)
+	}
+	return balance, nil
+}
+
+// checkFunds checks that a balance covers a value and fee
+func checkFunds(address common.Address, balance *big.Int, value *big.Int, fee *big.Int) error {
+	required := new(big.Int).Add(value, fee)
+	if balance.Cmp(required) >= 0 {
+		return nil
+	}
+	shortfall := new(big.Int).Sub(required, balance)
+	return &codedError{code: exitInsufficientFunds, err: fmt.Errorf("insufficient funds: balance of %s is %s, which is %s short of the value %s plus maximum fee %s; use --skip-balance-check to send anyway",
+		address.Hex(), etherutils.WeiToString(balance, true), etherutils.WeiToString(shortfall, true), etherutils.WeiToString(value, true), etherutils.WeiToString(fee, true))}
+}
+
+// reportFee prints an amount of gas and the resultant fee, unless in quiet mode
+func reportFee(label string, gas uint64, msg ethereum.CallMsg) {
+	if quiet {
+		return
+	}
+	fmt.Println(label, gas)
+	if msg.GasFeeCap != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), msg.GasFeeCap)
+		fmt.Println("Maximum fee:", etherutils.WeiToString(fee, true))
+	} else {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), msg.GasPrice)
+		fmt.Println("Estimated fee:", etherutils.WeiToString(fee, true))
+	}
+}
+
+// writeUnsignedTransaction writes an unsigned transaction as a line of JSON
+// to the file given by --output-tx, replacing what was in the file before
+// the first transaction of the command
+func writeUnsignedTransaction(tx *types.Transaction) error {
+	unsigned := &unsignedTransaction{
+		From:    outputTxFrom,
+		ChainID: (*hexutil.Big)(chainID),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		To:      tx.To(),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Data:    tx.Data(),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		unsigned.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		unsigned.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		unsigned.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return err
+	}
+	// Commands that create several transactions write one per line
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !outputTxWritten {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(outputTxFile, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	outputTxWritten = true
+	return nil
+}
+
+// printTransaction prints the details of a transaction
+func printTransaction(tx *types.Transaction) {
+	if tx.To() != nil {
+		fmt.Println("To:", tx.To().Hex())
+	}
+	fmt.Println("Nonce:", tx.Nonce())
+	fmt.Println("Value:", etherutils.WeiToString(tx.Value(), true))
+	fmt.Println("Gas limit:", tx.Gas())
+	if tx.Type() == types.DynamicFeeTxType {
+		fmt.Println("Maximum fee per gas:", etherutils.WeiToString(tx.GasFeeCap(), true))
+		fmt.Println("Priority fee per gas:", etherutils.WeiToString(tx.GasTipCap(), true))
+	} else {
+		fmt.Println("Gas price:", etherutils.WeiToString(tx.GasPrice(), true))
+	}
+	fmt.Println("Data:", hexutil.Encode(tx.Data()))
+}
@@ -15,11 +15,8 @@ package cmd
 
 import (
 	"bytes"
-	"fmt"
-	"math/big"
 	"strings"
 
-	etherutils "github.com/orinocopay/go-etherutils"
 	"github.com/orinocopay/go-etherutils/cli"
 	"github.com/orinocopay/go-etherutils/ens"
 	log "github.com/sirupsen/logrus"
@@ -38,53 +35,38 @@ var transferCmd = &cobra.Command{
 
 The keystore for the address must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
 
-In quiet mode this will return 0 if the transaction to transfer the name is sent successfully, otherwise 1.`,
+In quiet mode this will return 0 if the transaction to transfer the name is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(transferAddressStr != "", quiet, "Address to which to transfer ownership of the name is required")
-		cli.Assert(len(args[0]) > 10, quiet, "Name must be at least 7 characters long")
-		cli.Assert(len(strings.Split(args[0], ".")) == 2, quiet, "Name must not contain . (except for ending in .eth)")
+		assert(transferAddressStr != "", exitBadInput, "Address to which to transfer ownership of the name is required")
+		assert(len(args[0]) > 10, exitBadInput, "Name must be at least 7 characters long")
+		assert(len(strings.Split(args[0], ".")) == 2, exitBadInput, "Name must not contain . (except for ending in .eth)")
 
 		// Ensure that the name is in a suitable state
-		registrarContract, err := ens.RegistrarContract(client)
-		inState, err := ens.NameInState(registrarContract, client, args[0], "Owned")
-		cli.ErrAssert(inState, err, quiet, "Name not in a suitable state to transfer")
-
-		// Obtain the registry contract
-		registryContract, err := ens.RegistryContract(client)
+		state, err := ensClient.AuctionState(commandCtx, args[0])
+		cli.ErrAssert(state == "Owned", err, quiet, "Name not in a suitable state to transfer")
 
 		// Fetch the owner of the name
-		cli.ErrCheck(err, quiet, "Invalid name")
-		owner, err := registryContract.Owner(nil, ens.NameHash(args[0]))
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		errCheck(badInput(err), quiet, "Invalid name")
+		owner, err := registryContract.Owner(callOpts(), ens.NameHash(args[0]))
+		errCheck(err, quiet, "Cannot obtain owner")
+		assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, exitNotFound, "Owner is not set")
 
 		// Fetch the wallet and account for the owner
-		wallet, err := cli.ObtainWallet(chainID, owner)
-		cli.ErrCheck(err, quiet, "Failed to obtain a wallet for the owner")
-		account, err := cli.ObtainAccount(&wallet, &owner, passphrase)
-		cli.ErrCheck(err, quiet, "Failed to obtain an account for the owner")
+		wallet, account, err := obtainWalletAndAccount(owner, passphrase)
+		errCheck(err, quiet, "Failed to obtain an account for the owner")
 
-		gasPrice, err := etherutils.StringToWei(gasPriceStr)
-		cli.ErrCheck(err, quiet, "Invalid gas price")
+		gasPrice, err := gasPriceFlag()
+		errCheck(badInput(err), quiet, "Invalid gas price")
 
-		// Set up our session
-		session := ens.CreateRegistrarSession(chainID, &wallet, account, passphrase, registrarContract, gasPrice)
-		if nonce != -1 {
-			session.TransactOpts.Nonce = big.NewInt(nonce)
-		}
+		opts := generateTransactOpts(&wallet, account, passphrase, gasPrice)
 
 		// Transfer the deed
-		transferAddress, err := ens.Resolve(client, transferAddressStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain transfer address")
-		tx, err := ens.Transfer(session, args[0], transferAddress)
-		cli.ErrCheck(err, quiet, "Failed to send transaction")
-		if !quiet {
-			fmt.Println("Transaction ID is", tx.Hash().Hex())
-		}
-		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
-			"name":      args[0],
-			"networkid": chainID,
-			"address":   transferAddress.Hex()}).Info("Transfer")
+		transferAddress, err := resolveAddress(transferAddressStr)
+		errCheck(err, quiet, "Failed to obtain transfer address")
+		tx, err := ensClient.TransferDeed(commandCtx, opts, args[0], transferAddress)
+		errCheck(err, quiet, "Failed to send transaction")
+		handleTransaction(tx, log.Fields{"name": args[0],
+			"address": transferAddress.Hex()}, "Transfer")
 	},
 }
 
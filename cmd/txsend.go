@@ -0,0 +1,82 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var txSendRaw string
+
+// txSendCmd represents the tx send command
+var txSendCmd = &cobra.Command{
+	Use:   "send [file]",
+	Short: "Send a signed transaction",
+	Long: `Send a signed transaction to the network.  For example:
+
+    ens tx send signed.txt
+
+The file should contain the signed RLP-encoded transaction as 0x-prefixed hex.  Alternatively the transaction can be supplied directly:
+
+    ens tx send --raw=0xf86b...
+
+With --wait this will wait for the transaction to be mined and report its status.  Unsigned transactions for signing elsewhere can be created by supplying --output-tx to any command that sends a transaction.
+
+In quiet mode this will return 0 if the transaction is sent successfully, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input := txSendRaw
+		if input == "" {
+			assert(len(args) == 1, exitBadInput, "A transaction file or --raw is required")
+			data, err := ioutil.ReadFile(args[0])
+			errCheck(err, quiet, "Failed to read transaction file")
+			input = string(data)
+		} else {
+			assert(len(args) == 0, exitBadInput, "Only one of a transaction file or --raw can be supplied")
+		}
+		raw, err := hexutil.Decode(strings.TrimSpace(input))
+		errCheck(err, quiet, "Transaction is not valid hex")
+		tx := new(types.Transaction)
+		err = tx.UnmarshalBinary(raw)
+		errCheck(badInput(err), quiet, "Invalid transaction")
+
+		err = sendTransaction(tx)
+		errCheck(err, quiet, "Failed to send transaction")
+		if !quiet {
+			fmt.Println("Transaction ID is", tx.Hash().Hex())
+		}
+		log.WithFields(log.Fields{"transactionid": tx.Hash().Hex(),
+			"networkid": chainID}).Info("Transaction send")
+
+		if wait {
+			waitForTransaction(tx)
+		}
+	},
+}
+
+func init() {
+	txCmd.AddCommand(txSendCmd)
+
+	txSendCmd.Flags().StringVar(&txSendRaw, "raw", "", "Signed transaction as 0x-prefixed hex")
+	txSendCmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for the transaction to be mined")
+	txSendCmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 10*time.Minute, "Time to wait for the transaction to be mined")
+}
@@ -0,0 +1,128 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// version is the version of the tool, set at build time with
+//
+//	go build -ldflags "-X github.com/orinocopay/ens/cmd.version=1.2.3"
+var version = "dev"
+
+type versionNetworkResult struct {
+	Name          string `json:"name"`
+	ChainID       uint64 `json:"chainid"`
+	Registry      string `json:"registry"`
+	BaseRegistrar string `json:"baseregistrar"`
+	Controller    string `json:"controller"`
+	NameWrapper   string `json:"namewrapper"`
+	// PublicResolver, ReverseRegistrar and BulkRenewal are only known if set
+	// in the config file
+	PublicResolver   string `json:"publicresolver,omitempty"`
+	ReverseRegistrar string `json:"reverseregistrar,omitempty"`
+	BulkRenewal      string `json:"bulkrenewal,omitempty"`
+}
+
+type versionResult struct {
+	Version   string                  `json:"version"`
+	GoVersion string                  `json:"goversion"`
+	Networks  []*versionNetworkResult `json:"networks"`
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Display the version of the tool and the contracts it uses",
+	Long: `Display the version of the tool, the version of Go with which it was built and the addresses of the ENS contracts it uses.  For example:
+
+    ens version
+
+The contracts for all known networks, including those added in the config file, are shown unless --network is supplied, in which case only those for that network are shown.  This command does not connect to an Ethereum node.
+
+In quiet mode this will return 0 if the network is known, otherwise a non-zero exit code (see 'ens --help' for the exit codes).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var selected []*network
+		if networkName != "" {
+			selectedNetwork, err := networkByName(networkName)
+			errCheck(badInput(err), quiet, "Invalid network")
+			selected = append(selected, selectedNetwork)
+		} else {
+			for _, network := range networks {
+				selected = append(selected, network)
+			}
+			sort.Slice(selected, func(i, j int) bool { return selected[i].chainID < selected[j].chainID })
+		}
+		if quiet {
+			return
+		}
+
+		result := &versionResult{
+			Version:   version,
+			GoVersion: runtime.Version(),
+		}
+		for _, network := range selected {
+			networkResult := &versionNetworkResult{
+				Name:          network.name,
+				ChainID:       network.chainID,
+				Registry:      network.registry.Hex(),
+				BaseRegistrar: network.baseRegistrar.Hex(),
+				Controller:    network.controller.Hex(),
+				NameWrapper:   network.nameWrapper.Hex(),
+			}
+			if network.publicResolver != (common.Address{}) {
+				networkResult.PublicResolver = network.publicResolver.Hex()
+			}
+			if network.reverseRegistrar != (common.Address{}) {
+				networkResult.ReverseRegistrar = network.reverseRegistrar.Hex()
+			}
+			if network.bulkRenewal != (common.Address{}) {
+				networkResult.BulkRenewal = network.bulkRenewal.Hex()
+			}
+			result.Networks = append(result.Networks, networkResult)
+		}
+		if jsonOutput {
+			outputJSON(result)
+			return
+		}
+		fmt.Printf("Version: %s\n", result.Version)
+		fmt.Printf("Go version: %s\n", result.GoVersion)
+		for _, network := range result.Networks {
+			fmt.Printf("Network: %s (chain %d)\n", network.Name, network.ChainID)
+			fmt.Printf("  Registry: %s\n", network.Registry)
+			fmt.Printf("  Base registrar: %s\n", network.BaseRegistrar)
+			fmt.Printf("  Controller: %s\n", network.Controller)
+			fmt.Printf("  NameWrapper: %s\n", network.NameWrapper)
+			if network.PublicResolver != "" {
+				fmt.Printf("  Public resolver: %s\n", network.PublicResolver)
+			}
+			if network.ReverseRegistrar != "" {
+				fmt.Printf("  Reverse registrar: %s\n", network.ReverseRegistrar)
+			}
+			if network.BulkRenewal != "" {
+				fmt.Printf("  Bulk renewal: %s\n", network.BulkRenewal)
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(versionCmd)
+}
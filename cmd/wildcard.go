@@ -0,0 +1,82 @@
+// Copyright © 2017 Orinoco Payments
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var ccipTimeout time.Duration
+var ccipAllow []string
+
+// resolutionSource is where the answer to a resolution came from
+type resolutionSource struct {
+	// gateway is the URL of the gateway that answered an offchain lookup,
+	// or empty if the answer came from onchain
+	gateway string
+	// l2 is the name of the L2 from whose state the gateway answered, if known
+	l2 string
+}
+
+// String returns onchain, gateway or the name of the L2
+func (s *resolutionSource) String() string {
+	switch {
+	case s.gateway == "":
+		return "onchain"
+	case s.l2 != "":
+		return "L2 (" + s.l2 + ")"
+	default:
+		return "gateway"
+	}
+}
+
+// newResolutionSource creates the source of a resolution answered by a
+// gateway, or onchain if the gateway is empty
+func newResolutionSource(gateway string) *resolutionSource {
+	source := &resolutionSource{gateway: gateway}
+	if gateway != "" && currentNetwork != nil {
+		if u, err := url.Parse(gateway); err == nil {
+			source.l2 = currentNetwork.l2Gateways[strings.ToLower(u.Hostname())]
+		}
+	}
+	return source
+}
+
+// resolveAddress resolves the Ethereum address of a name, supporting
+// ENSIP-10 wildcard resolution and EIP-3668 offchain lookups
+func resolveAddress(name string) (common.Address, error) {
+	address, _, err := resolveAddressWithSource(name)
+	return address, err
+}
+
+// resolveAddressWithSource resolves the address of a name as resolveAddress,
+// also returning where the answer came from
+func resolveAddressWithSource(name string) (common.Address, *resolutionSource, error) {
+	if common.IsHexAddress(name) {
+		return common.HexToAddress(name), newResolutionSource(""), nil
+	}
+	address, gateway, err := ensClient.ResolveAddress(commandCtx, name)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if gateway != "" {
+		log.WithFields(log.Fields{"name": name, "gateway": gateway}).Info("Offchain lookup answered")
+	}
+	return address, newResolutionSource(gateway), nil
+}